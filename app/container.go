@@ -3,10 +3,13 @@ package container
 import (
 	"context"
 	"next-oms/app/http/controllers"
+	"next-oms/app/http/middlewares"
 	repoImpl "next-oms/app/repository/impl"
 	svcImpl "next-oms/app/svc/impl"
 	"next-oms/infra/conn/cache"
 	"next-oms/infra/conn/db"
+	"next-oms/infra/conn/mail"
+	"next-oms/infra/conn/storage"
 	"next-oms/infra/logger"
 )
 
@@ -14,20 +17,48 @@ func Init(g interface{}, lc logger.LogClient) {
 	basectx := context.Background()
 	dbc := db.Client()
 	cachec := cache.Client()
+	mailc := mail.Client()
+	storagec := storage.Client()
 
 	// register all repos impl, services impl, controllers
 	sysRepo := repoImpl.NewSystemRepository(basectx, lc, dbc, cachec)
 	userRepo := repoImpl.NewUsersRepository(basectx, lc, dbc)
 	orderRepo := repoImpl.NewOrdersRepository(basectx, lc, dbc)
+	shipmentRepo := repoImpl.NewShipmentsRepository(basectx, lc, dbc)
+	rateCardRepo := repoImpl.NewRateCardsRepository(basectx, lc, dbc)
+	locationRepo := repoImpl.NewLocationsRepository(basectx, lc, dbc)
+	storeRepo := repoImpl.NewStoresRepository(basectx, lc, dbc)
+	roleRepo := repoImpl.NewRolesRepository(basectx, lc, dbc)
+	companyRepo := repoImpl.NewCompaniesRepository(basectx, lc, dbc)
+	apiKeyRepo := repoImpl.NewAPIKeysRepository(basectx, lc, dbc)
 
 	sysSvc := svcImpl.NewSystemService(sysRepo)
-	userSvc := svcImpl.NewUsersService(basectx, lc, userRepo)
+	mailSvc := svcImpl.NewMailsService(basectx, lc, mailc)
 	tokenSvc := svcImpl.NewTokenService(basectx, lc, userRepo)
-	authSvc := svcImpl.NewAuthService(basectx, lc, userRepo, tokenSvc)
-	orderSvc := svcImpl.NewOrdersService(basectx, lc, orderRepo)
+	userSvc := svcImpl.NewUsersService(basectx, lc, userRepo, companyRepo, roleRepo, storagec, mailSvc, tokenSvc)
+	authSvc := svcImpl.NewAuthService(basectx, lc, userRepo, roleRepo, tokenSvc, mailSvc)
+	twoFactorSvc := svcImpl.NewTwoFactorService(basectx, lc, userRepo, roleRepo)
+	pricingSvc := svcImpl.NewPricingService(basectx, lc, rateCardRepo)
+	locationSvc := svcImpl.NewLocationsService(basectx, lc, locationRepo)
+	storeSvc := svcImpl.NewStoresService(basectx, lc, storeRepo, locationSvc, userSvc)
+	roleSvc := svcImpl.NewRolesService(basectx, lc, roleRepo)
+	companySvc := svcImpl.NewCompaniesService(basectx, lc, companyRepo)
+	apiKeySvc := svcImpl.NewAPIKeysService(basectx, lc, apiKeyRepo, userRepo)
+	orderSvc := svcImpl.NewOrdersService(basectx, lc, orderRepo, shipmentRepo, pricingSvc, storeSvc, locationSvc, userSvc)
+
+	middlewares.SetTokenUserResolver(userSvc)
+	middlewares.SetAPIKeyResolver(apiKeySvc)
 
 	controllers.NewSystemController(g, lc, sysSvc)
 	controllers.NewAuthController(g, lc, authSvc, userSvc)
 	controllers.NewUsersController(g, lc, userSvc)
 	controllers.NewOrdersController(g, lc, orderSvc)
+	controllers.NewPricingController(g, lc, pricingSvc)
+	controllers.NewLocationsController(g, lc, locationSvc)
+	controllers.NewStoresController(g, lc, storeSvc)
+	controllers.NewRolesController(g, lc, roleSvc)
+	controllers.NewCompaniesController(g, lc, companySvc, userSvc)
+	controllers.NewAdminUsersController(g, lc, userSvc)
+	controllers.NewAPIKeysController(g, lc, apiKeySvc)
+	controllers.NewTwoFactorController(g, lc, twoFactorSvc)
 }
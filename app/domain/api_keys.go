@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"next-oms/infra/errors"
+	"time"
+)
+
+type IAPIKeys interface {
+	SaveAPIKey(key *APIKey) (*APIKey, *errors.RestErr)
+	GetAPIKeysByUser(userID uint) (APIKeys, *errors.RestErr)
+	GetAPIKeyByPrefix(prefix string) (*APIKey, *errors.RestErr)
+	RevokeAPIKey(userID, id uint) *errors.RestErr
+	SetAPIKeyUsed(id uint, usedAt time.Time) error
+}
+
+// APIKey lets a merchant system call the api on behalf of the user who created it, only the
+// hash of the key is stored
+type APIKey struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes" gorm:"-"`
+	UserID     uint       `json:"user_id"`
+	CompanyID  uint       `json:"company_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type APIKeys []*APIKey
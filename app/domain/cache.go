@@ -4,9 +4,15 @@ import "context"
 
 type ICache interface {
 	Set(ctx context.Context, key string, value interface{}, ttl int) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl int) (bool, error)
+	Incr(ctx context.Context, key string, ttl int) (int64, error)
 	Get(ctx context.Context, key string) (string, error)
 	GetInt(ctx context.Context, key string) (int, error)
 	GetStruct(ctx context.Context, key string, outputStruct interface{}) error
 	Del(ctx context.Context, keys ...string) error
+	Consume(ctx context.Context, key string) (bool, error)
 	DelPattern(ctx context.Context, pattern string) error
+	SAdd(ctx context.Context, key string, ttl int, members ...string) error
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SRem(ctx context.Context, key string, members ...string) error
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+	"time"
+)
+
+type ICompanies interface {
+	SaveBusiness(business *Business) (*Business, *errors.RestErr)
+	GetBusinesses(filters *serializers.ListFilters) (Businesses, *errors.RestErr)
+	GetBusinessByID(id uint) (*Business, *errors.RestErr)
+	UpdateBusiness(business *Business) *errors.RestErr
+	DeleteBusiness(id uint) *errors.RestErr
+	SaveCompany(company *Company) (*Company, *errors.RestErr)
+	GetCompanies(filters *serializers.ListFilters) (Companies, *errors.RestErr)
+	GetCompanyByID(id uint) (*Company, *errors.RestErr)
+	UpdateCompany(company *Company) *errors.RestErr
+	DeleteCompany(id uint) *errors.RestErr
+}
+
+// Business is the group a set of companies belongs to
+type Business struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Businesses []*Business
+
+// Company is the merchant tenant, its users share the company's orders
+type Company struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	BusinessID *uint     `json:"business_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Companies []*Company
+
+// TenantScope is the tenant an order or store query runs for. Members of a company share the
+// company's orders & stores, users without a company only reach their own and admins reach every tenant
+type TenantScope struct {
+	UserID     uint
+	CompanyID  *uint
+	AllTenants bool
+}
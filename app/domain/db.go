@@ -3,4 +3,11 @@ package domain
 type IDb interface {
 	IUsers
 	IOrders
+	IShipments
+	IRateCards
+	ILocations
+	IStores
+	IRoles
+	ICompanies
+	IAPIKeys
 }
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"next-oms/infra/errors"
+	"time"
+)
+
+type ILocations interface {
+	SaveCity(city *City) (*City, *errors.RestErr)
+	GetCities() (Cities, *errors.RestErr)
+	GetCityByID(id uint) (*City, *errors.RestErr)
+	UpdateCity(city *City) *errors.RestErr
+	DeleteCity(id uint) *errors.RestErr
+
+	SaveZone(zone *Zone) (*Zone, *errors.RestErr)
+	GetZones(cityID uint) (Zones, *errors.RestErr)
+	GetZoneByID(id uint) (*Zone, *errors.RestErr)
+	GetZonesByIDs(ids []uint) (Zones, *errors.RestErr)
+	UpdateZone(zone *Zone) *errors.RestErr
+	DeleteZone(id uint) *errors.RestErr
+
+	SaveArea(area *Area) (*Area, *errors.RestErr)
+	GetAreas(zoneID uint) (Areas, *errors.RestErr)
+	GetAreaByID(id uint) (*Area, *errors.RestErr)
+	GetAreasByIDs(ids []uint) (Areas, *errors.RestErr)
+	UpdateArea(area *Area) *errors.RestErr
+	DeleteArea(id uint) *errors.RestErr
+}
+
+type City struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Cities []*City
+
+type Zone struct {
+	ID        uint      `json:"id"`
+	CityID    uint      `json:"city_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Zones []*Zone
+
+type Area struct {
+	ID        uint      `json:"id"`
+	ZoneID    uint      `json:"zone_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Areas []*Area
@@ -0,0 +1,12 @@
+package domain
+
+type IMailer interface {
+	Send(mail *Mail) error
+}
+
+type Mail struct {
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
@@ -3,32 +3,60 @@ package domain
 import (
 	"next-oms/app/serializers"
 	"next-oms/infra/errors"
+	"time"
 )
 
 type IOrders interface {
-	SaveOrder(order *Order) (*Order, *errors.RestErr)
-	GetOrders(filters *serializers.ListFilters) (Orders, *errors.RestErr)
-	CancelOrder(conID string) *errors.RestErr
+	SaveOrder(order *Order, actorID uint) (*Order, *errors.RestErr)
+	SaveOrders(orders Orders, actorID uint) *errors.RestErr
+	GetOrders(scope *TenantScope, filters *serializers.ListFilters) (Orders, *errors.RestErr)
+	GetOrderByConsignmentID(scope *TenantScope, conID string) (*Order, *errors.RestErr)
+	GetOrderByMerchantOrderID(scope *TenantScope, storeID int, merchantOrderID string) (*Order, *errors.RestErr)
+	GetMerchantOrders(storeIDs []int, merchantOrderIDs []string) (Orders, *errors.RestErr)
+	GetLastConsignmentID(prefix string) (string, *errors.RestErr)
+	UpdateOrderStatus(history *OrderHistory) *errors.RestErr
+	GetOrderHistory(conID string) (OrderHistories, *errors.RestErr)
 }
 
 type Order struct {
-	ConsignmentID    string  `json:"order_consignment_id"`
-	Description      string  `json:"order_description"`
-	MerchantOrderID  string  `json:"merchant_order_id"`
-	RecipientName    string  `json:"recipient_name"`
-	RecipientAddress string  `json:"recipient_address"`
-	RecipientPhone   string  `json:"recipient_phone"`
-	Amount           float64 `json:"order_amount"`
-	TotalFee         float64 `json:"total_fee"`
-	Instruction      string  `json:"instruction"`
-	OrderTypeID      int     `json:"order_type_id"`
-	CodFee           float64 `json:"cod_fee"`
-	PromoDiscount    float64 `json:"promo_discount"`
-	Discount         float64 `json:"discount"`
-	DeliveryFee      float64 `json:"delivery_fee"`
-	Status           string  `json:"order_status"`
-	OrderType        string  `json:"order_type"`
-	ItemType         string  `json:"item_type"`
+	ConsignmentID    string    `json:"order_consignment_id"`
+	Description      string    `json:"order_description"`
+	CompanyID        *uint     `json:"company_id"`
+	CreatedBy        uint      `json:"created_by"`
+	StoreID          int       `json:"store_id"`
+	MerchantOrderID  string    `json:"merchant_order_id"`
+	RecipientName    string    `json:"recipient_name"`
+	RecipientAddress string    `json:"recipient_address"`
+	RecipientPhone   string    `json:"recipient_phone"`
+	Amount           float64   `json:"order_amount"`
+	TotalFee         float64   `json:"total_fee"`
+	Instruction      string    `json:"instruction"`
+	OrderTypeID      int       `json:"order_type_id"`
+	CodFee           float64   `json:"cod_fee"`
+	PromoDiscount    float64   `json:"promo_discount"`
+	Discount         float64   `json:"discount"`
+	DeliveryFee      float64   `json:"delivery_fee"`
+	ItemFee          float64   `json:"item_fee"`
+	WeightFee        float64   `json:"weight_fee"`
+	ZoneSurcharge    float64   `json:"zone_surcharge"`
+	RateCardID       uint      `json:"rate_card_id"`
+	RateCardVersion  int       `json:"rate_card_version"`
+	Status           string    `json:"order_status"`
+	OrderType        string    `json:"order_type"`
+	ItemType         string    `json:"item_type"`
+	Shipment         *Shipment `json:"shipment,omitempty" gorm:"-"`
 }
 
 type Orders []*Order
+
+// OrderHistory is a single status change of an order
+type OrderHistory struct {
+	ConsignmentID string    `json:"order_consignment_id"`
+	OldStatus     string    `json:"old_status"`
+	NewStatus     string    `json:"new_status"`
+	ActorID       uint      `json:"actor_id"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type OrderHistories []*OrderHistory
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+	"time"
+)
+
+type IRateCards interface {
+	SaveRateCard(card *RateCard) (*RateCard, *errors.RestErr)
+	GetRateCards(filters *serializers.ListFilters) (RateCards, *errors.RestErr)
+	GetRateCardByID(id uint) (*RateCard, *errors.RestErr)
+	GetActiveRateCard() (*RateCard, *errors.RestErr)
+	UpdateRateCard(card *RateCard) (*RateCard, *errors.RestErr)
+	DeleteRateCard(id uint) *errors.RestErr
+}
+
+// RateCard is a versioned price list used to calculate order fees, every update adds a new
+// version with its own id & charges so the version which priced an order stays as it was
+type RateCard struct {
+	ID              uint              `json:"id"`
+	CardID          uint              `json:"card_id"`
+	Name            string            `json:"name"`
+	Version         int               `json:"version"`
+	Active          bool              `json:"active"`
+	SupersededAt    *time.Time        `json:"superseded_at,omitempty"`
+	CodPercentage   float64           `json:"cod_percentage"`
+	DeliveryCharges []*DeliveryCharge `json:"delivery_charges"`
+	ItemCharges     []*ItemCharge     `json:"item_charges"`
+	ZoneCharges     []*ZoneCharge     `json:"zone_charges"`
+	WeightBands     []*WeightBand     `json:"weight_bands"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+type RateCards []*RateCard
+
+type DeliveryCharge struct {
+	DeliveryType int     `json:"delivery_type"`
+	Fee          float64 `json:"fee"`
+}
+
+type ItemCharge struct {
+	ItemType int     `json:"item_type"`
+	Fee      float64 `json:"fee"`
+}
+
+type ZoneCharge struct {
+	ZoneID    int     `json:"zone_id"`
+	Surcharge float64 `json:"surcharge"`
+}
+
+// WeightBand applies its fee to the weights in [MinWeight, MaxWeight)
+type WeightBand struct {
+	MinWeight float64 `json:"min_weight"`
+	MaxWeight float64 `json:"max_weight"`
+	Fee       float64 `json:"fee"`
+}
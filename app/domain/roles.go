@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"next-oms/infra/errors"
+	"time"
+)
+
+type IRoles interface {
+	SaveRole(role *Role) (*Role, *errors.RestErr)
+	GetRoles() (Roles, *errors.RestErr)
+	GetRoleByID(id uint) (*Role, *errors.RestErr)
+	UpdateRole(role *Role) *errors.RestErr
+	DeleteRole(id uint) *errors.RestErr
+	SetRolePermissions(roleID uint, permissionIDs []uint) *errors.RestErr
+	SavePermission(permission *Permission) (*Permission, *errors.RestErr)
+	GetPermissions() (Permissions, *errors.RestErr)
+	GetPermissionByID(id uint) (*Permission, *errors.RestErr)
+	GetPermissionsByIDs(ids []uint) (Permissions, *errors.RestErr)
+	UpdatePermission(permission *Permission) *errors.RestErr
+	DeletePermission(id uint) *errors.RestErr
+}
+
+// Role groups the permissions granted to the users holding it
+type Role struct {
+	ID               uint        `json:"id"`
+	Name             string      `json:"name"`
+	Description      string      `json:"description"`
+	RequireTwoFactor bool        `json:"require_two_factor"`
+	Permissions      Permissions `json:"permissions,omitempty" gorm:"-"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type Roles []*Role
+
+// Permission is a single action a route can be restricted to, eg: order.cancel
+type Permission struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Permissions []*Permission
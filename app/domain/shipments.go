@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"next-oms/infra/errors"
+	"time"
+)
+
+type IShipments interface {
+	GetShipmentByConsignmentID(conID string) (*Shipment, *errors.RestErr)
+}
+
+// Shipment holds the recipient location and parcel details of an order
+type Shipment struct {
+	ConsignmentID string    `json:"order_consignment_id"`
+	StoreID       int       `json:"store_id"`
+	RecipientCity int       `json:"recipient_city"`
+	RecipientZone int       `json:"recipient_zone"`
+	RecipientArea int       `json:"recipient_area"`
+	DeliveryType  int       `json:"delivery_type"`
+	ItemType      int       `json:"item_type"`
+	ItemQuantity  int       `json:"item_quantity"`
+	ItemWeight    float64   `json:"item_weight"`
+	ItemLength    float64   `json:"item_length"`
+	ItemWidth     float64   `json:"item_width"`
+	ItemHeight    float64   `json:"item_height"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// IStorage keeps the uploaded files, the files are served from their URL
+type IStorage interface {
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+	// Key returns the key of a URL of the storage, false for a URL served from elsewhere
+	Key(url string) (string, bool)
+}
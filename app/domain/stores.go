@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+	"time"
+)
+
+type IStores interface {
+	SaveStore(store *Store) (*Store, *errors.RestErr)
+	GetStores(scope *TenantScope, filters *serializers.ListFilters) (Stores, *errors.RestErr)
+	GetStoreByID(scope *TenantScope, id uint) (*Store, *errors.RestErr)
+	GetStoresByIDs(scope *TenantScope, ids []uint) (Stores, *errors.RestErr)
+	UpdateStore(store *Store) *errors.RestErr
+	DeleteStore(id uint) *errors.RestErr
+}
+
+// Store is a pickup point of a merchant, orders are created against a store
+type Store struct {
+	ID           uint      `json:"id"`
+	UserID       uint      `json:"user_id"`
+	CompanyID    *uint     `json:"company_id"`
+	Name         string    `json:"name"`
+	ContactPhone string    `json:"contact_phone"`
+	Address      string    `json:"address"`
+	CityID       uint      `json:"city_id"`
+	ZoneID       uint      `json:"zone_id"`
+	AreaID       uint      `json:"area_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Stores []*Store
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"next-oms/app/serializers"
 	"next-oms/infra/errors"
 	"time"
 )
@@ -10,26 +11,49 @@ type IUsers interface {
 	GetUserByID(userID uint) (*User, *errors.RestErr)
 	GetUserByEmail(email string) (*User, error)
 	UpdateUser(user *User) *errors.RestErr
-	UpdatePassword(userID uint, companyID uint, updateValue map[string]interface{}) *errors.RestErr
+	SetUserPassword(userID uint, hashedPass []byte, historySize int) *errors.RestErr
+	GetPasswordHistory(userID uint, limit int) ([]string, *errors.RestErr)
 	SetLastLoginAt(user *User) error
-	ResetPassword(userID int, hashedPass []byte) error
 	GetTokenUser(id uint) (*VerifyTokenResp, *errors.RestErr)
+	GetUsersByCompany(companyID uint, filters *serializers.ListFilters) (Users, *errors.RestErr)
+	GetUsers(filters *serializers.ListFilters, deleted bool) (Users, *errors.RestErr)
+	GetDeletedUser(userID uint) (*User, *errors.RestErr)
+	DeleteUser(userID uint) *errors.RestErr
+	RestoreUser(userID uint) *errors.RestErr
+	SetUserRole(userID, roleID uint) *errors.RestErr
+	SetUserProfilePic(userID uint, profilePic *string) *errors.RestErr
+	SetUserCompany(userID uint, companyID *uint) *errors.RestErr
+	SetUserActive(userID uint, active bool) *errors.RestErr
+	SetUserVerified(userID uint) *errors.RestErr
+	SetUserEmail(userID uint, email string) *errors.RestErr
+	SetUserTotpSecret(userID uint, secret string) *errors.RestErr
+	EnableUserTwoFactor(userID uint, backupCodeHashes []string) *errors.RestErr
+	DisableUserTwoFactor(userID uint) *errors.RestErr
+	SetUserBackupCodes(userID uint, backupCodeHashes []string) *errors.RestErr
+	UseBackupCode(userID uint, backupCodeHash string) (bool, error)
 }
 
 type User struct {
-	ID          uint       `json:"id"`
-	UserName    string     `json:"user_name"`
-	FirstName   string     `json:"first_name"`
-	LastName    string     `json:"last_name"`
-	Email       string     `json:"email"`
-	Password    *string    `json:"password,omitempty"`
-	Phone       string     `json:"phone"`
-	ProfilePic  *string    `json:"profile_pic"`
-	LastLoginAt *time.Time `json:"last_login_at"`
-	FirstLogin  bool       `json:"first_login" gorm:"column:first_login;default:true"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at"`
+	ID                uint       `json:"id"`
+	UserName          string     `json:"user_name"`
+	FirstName         string     `json:"first_name"`
+	LastName          string     `json:"last_name"`
+	Email             string     `json:"email"`
+	Password          *string    `json:"password,omitempty"`
+	Phone             string     `json:"phone"`
+	ProfilePic        *string    `json:"profile_pic"`
+	CompanyID         *uint      `json:"company_id"`
+	RoleID            uint       `json:"role_id"`
+	Active            bool       `json:"active" gorm:"default:true"`
+	VerifiedAt        *time.Time `json:"verified_at"`
+	TotpSecret        *string    `json:"-"`
+	TwoFactorAt       *time.Time `json:"two_factor_at"`
+	PasswordChangedAt *time.Time `json:"password_changed_at"`
+	LastLoginAt       *time.Time `json:"last_login_at"`
+	FirstLogin        bool       `json:"first_login" gorm:"column:first_login;default:true"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at"`
 }
 
 type Users []*User
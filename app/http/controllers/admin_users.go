@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/domain"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/methodsutil"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type adminUsers struct {
+	lc   logger.LogClient
+	uSvc svc.IUsers
+}
+
+// NewAdminUsersController will initialize the controllers
+func NewAdminUsersController(grp interface{}, lc logger.LogClient, uSvc svc.IUsers) {
+	ac := &adminUsers{
+		lc:   lc,
+		uSvc: uSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionUserManage)
+
+	g.POST("/v1/admin/users", ac.CreateUser, manage)
+	g.GET("/v1/admin/users", ac.GetUsers, manage)
+	g.GET("/v1/admin/users/deleted", ac.GetDeletedUsers, manage)
+	g.GET("/v1/admin/users/:id", ac.GetUser, manage)
+	g.DELETE("/v1/admin/users/:id", ac.DeleteUser, manage)
+	g.PATCH("/v1/admin/users/:id/restore", ac.RestoreUser, manage)
+	g.PATCH("/v1/admin/users/:id/activate", ac.ActivateUser, manage)
+	g.PATCH("/v1/admin/users/:id/deactivate", ac.DeactivateUser, manage)
+	g.POST("/v1/admin/users/:id/password-reset", ac.ForcePasswordReset, manage)
+	g.PUT("/v1/admin/users/:id/role", ac.AssignRole, manage)
+}
+
+// swagger:route POST /v1/admin/users AdminUser AdminCreateUser
+// Create a verified user with a role & company
+// responses:
+//	201: UserCreatedResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// CreateUser handles POST requests and create a new user
+func (ctr *adminUsers) CreateUser(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.AdminUserReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var user domain.User
+	if err := methodsutil.StructToStruct(req, &user); err != nil {
+		ctr.lc.Error(msgutil.EntityStructToStructFailedMsg("set admin user request to user"), err)
+		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.uSvc.CreateAdminUser(uint(loggedInUser.ID), user)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	var resp serializers.UserResp
+	if err := methodsutil.StructToStruct(result, &resp); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// swagger:route GET /v1/admin/users AdminUser AdminGetUsers
+// List the users of every company, supports the list filters eg: ?email.contains=
+// responses:
+//	200: UserResponse
+//	401: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// GetUsers handles GET requests and return the users
+func (ctr *adminUsers) GetUsers(c echo.Context) error {
+	return ctr.getUsers(c, false)
+}
+
+// swagger:route GET /v1/admin/users/deleted AdminUser AdminGetDeletedUsers
+// List the soft deleted users, supports the list filters
+// responses:
+//	200: UserResponse
+//	401: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// GetDeletedUsers handles GET requests and return the soft deleted users
+func (ctr *adminUsers) GetDeletedUsers(c echo.Context) error {
+	return ctr.getUsers(c, true)
+}
+
+func (ctr *adminUsers) getUsers(c echo.Context, deleted bool) error {
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.uSvc.GetUsers(listParams, deleted)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /v1/admin/users/{id} AdminUser AdminGetUser
+// Get a single user
+// responses:
+//	200: AdminUserResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// GetUser handles GET requests and return a single user
+func (ctr *adminUsers) GetUser(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("user id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.uSvc.GetUserById(id)
+	if getErr != nil {
+		restErr := errors.NewNotFoundError("user not found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var resp serializers.UserResp
+	if err := methodsutil.StructToStruct(result, &resp); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// swagger:route DELETE /v1/admin/users/{id} AdminUser AdminDeleteUser
+// Soft delete a user & end its sessions
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// DeleteUser handles DELETE requests and soft delete a user
+func (ctr *adminUsers) DeleteUser(c echo.Context) error {
+	callerID, userID, restErr := ctr.callerAndUserID(c)
+	if restErr != nil {
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.uSvc.DeleteUser(callerID, userID); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("user")})
+}
+
+// swagger:route PATCH /v1/admin/users/{id}/restore AdminUser AdminRestoreUser
+// Restore a soft deleted user
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// RestoreUser handles PATCH requests and restore a soft deleted user
+func (ctr *adminUsers) RestoreUser(c echo.Context) error {
+	callerID, userID, restErr := ctr.callerAndUserID(c)
+	if restErr != nil {
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if restoreErr := ctr.uSvc.RestoreUser(callerID, userID); restoreErr != nil {
+		return c.JSON(restoreErr.Status, restoreErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "user restored"})
+}
+
+// swagger:route PATCH /v1/admin/users/{id}/activate AdminUser AdminActivateUser
+// Let a user log in again
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// ActivateUser handles PATCH requests and let a user log in again
+func (ctr *adminUsers) ActivateUser(c echo.Context) error {
+	return ctr.setUserActive(c, true)
+}
+
+// swagger:route PATCH /v1/admin/users/{id}/deactivate AdminUser AdminDeactivateUser
+// Stop a user from logging in & end its sessions
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// DeactivateUser handles PATCH requests and stop a user from logging in
+func (ctr *adminUsers) DeactivateUser(c echo.Context) error {
+	return ctr.setUserActive(c, false)
+}
+
+func (ctr *adminUsers) setUserActive(c echo.Context, active bool) error {
+	callerID, userID, restErr := ctr.callerAndUserID(c)
+	if restErr != nil {
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if setErr := ctr.uSvc.SetUserActiveByAdmin(callerID, userID, active); setErr != nil {
+		return c.JSON(setErr.Status, setErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user status")})
+}
+
+// swagger:route POST /v1/admin/users/{id}/password-reset AdminUser AdminForcePasswordReset
+// Replace the password of a user with an unusable one, end its sessions & mail it a reset link
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// ForcePasswordReset handles POST requests and make a user reset its password
+func (ctr *adminUsers) ForcePasswordReset(c echo.Context) error {
+	callerID, userID, restErr := ctr.callerAndUserID(c)
+	if restErr != nil {
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if resetErr := ctr.uSvc.ForcePasswordReset(callerID, userID); resetErr != nil {
+		return c.JSON(resetErr.Status, resetErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "Password reset link sent to email"})
+}
+
+// swagger:route PUT /v1/admin/users/{id}/role AdminUser AdminAssignRole
+// Replace the role of a user
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// AssignRole handles PUT requests and replace the role of a user
+func (ctr *adminUsers) AssignRole(c echo.Context) error {
+	callerID, userID, restErr := ctr.callerAndUserID(c)
+	if restErr != nil {
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.UserRoleReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if assignErr := ctr.uSvc.AssignRole(callerID, userID, req.RoleID); assignErr != nil {
+		return c.JSON(assignErr.Status, assignErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user role")})
+}
+
+// callerAndUserID returns the id of the logged-in user & the user id of the path
+func (ctr *adminUsers) callerAndUserID(c echo.Context) (uint, uint, *errors.RestErr) {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		return 0, 0, errors.NewUnauthorizedError("no logged-in user found")
+	}
+
+	userID, err := GetIDParam(c, "id")
+	if err != nil {
+		return 0, 0, errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("user id"))
+	}
+
+	return uint(loggedInUser.ID), userID, nil
+}
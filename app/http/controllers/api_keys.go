@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type apiKeys struct {
+	lc   logger.LogClient
+	kSvc svc.IAPIKeys
+}
+
+// NewAPIKeysController will initialize the controllers
+func NewAPIKeysController(grp interface{}, lc logger.LogClient, kSvc svc.IAPIKeys) {
+	kc := &apiKeys{
+		lc:   lc,
+		kSvc: kSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionAPIKeyManage)
+
+	g.POST("/v1/api-keys", kc.CreateAPIKey, manage)
+	g.GET("/v1/api-keys", kc.GetAPIKeys, manage)
+	g.DELETE("/v1/api-keys/:id", kc.RevokeAPIKey, manage)
+}
+
+// swagger:route POST /v1/api-keys APIKey CreateAPIKey
+// Create an api key for the logged-in user, the key is only shown once
+// responses:
+//	201: APIKeyCreatedResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// CreateAPIKey handles POST requests and create a new api key
+func (ctr *apiKeys) CreateAPIKey(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.APIKeyReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.kSvc.CreateAPIKey(uint(loggedInUser.ID), &req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// swagger:route GET /v1/api-keys APIKey GetAPIKeys
+// List the api keys of the logged-in user
+// responses:
+//	200: APIKeysResponse
+//	401: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// GetAPIKeys handles GET requests and return the api keys of the logged-in user
+func (ctr *apiKeys) GetAPIKeys(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.kSvc.GetAPIKeys(uint(loggedInUser.ID))
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /v1/api-keys/{id} APIKey RevokeAPIKey
+// Revoke an api key of the logged-in user
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// RevokeAPIKey handles DELETE requests and revoke an api key
+func (ctr *apiKeys) RevokeAPIKey(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("api key id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if revokeErr := ctr.kSvc.RevokeAPIKey(uint(loggedInUser.ID), id); revokeErr != nil {
+		return c.JSON(revokeErr.Status, revokeErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "api key revoked"})
+}
@@ -26,23 +26,31 @@ func NewAuthController(grp interface{}, lc logger.LogClient, authSvc svc.IAuth,
 	g := grp.(*echo.Group)
 
 	g.POST("/v1/login", ac.Login)
+	g.POST("/v1/login/2fa", ac.LoginTwoFactor)
+	g.POST("/v1/login/2fa/enroll", ac.EnrollTwoFactor)
 	g.POST("/v1/logout", ac.Logout)
 	g.POST("/v1/token/refresh", ac.RefreshToken)
 	g.GET("/v1/token/verify", ac.VerifyToken)
+	g.GET("/v1/sessions", ac.GetSessions)
+	g.DELETE("/v1/sessions", ac.RevokeSessions)
+	g.DELETE("/v1/sessions/:id", ac.RevokeSession)
 }
 
 // swagger:route POST /v1/login Auth Login
 // logged in a user
 // responses:
 //	201: LoginResp
+//	202: TwoFactorChallengeResponse
 //	400: errorResponse
 //	404: errorResponse
+//	429: errorResponse
 //	500: errorResponse
 
-// Login handles POST requests and logged in a user
+// Login handles POST requests and logged in a user, users with two factor get a challenge instead
 func (ctr *auth) Login(c echo.Context) error {
 	var cred *serializers.LoginReq
 	var resp *serializers.LoginResp
+	var challenge *serializers.TwoFactorChallengeResp
 	var err error
 
 	if err = c.Bind(&cred); err != nil {
@@ -51,11 +59,20 @@ func (ctr *auth) Login(c echo.Context) error {
 		return c.JSON(bodyErr.Status, bodyErr)
 	}
 
-	if resp, err = ctr.authSvc.Login(cred); err != nil {
+	if resp, challenge, err = ctr.authSvc.Login(cred, GetClientInfo(c)); err != nil {
 		switch err {
 		case errors.ErrInvalidEmail, errors.ErrInvalidPassword, errors.ErrNotAdmin:
 			unAuthErr := errors.NewUnauthorizedError("The user credentials were incorrect.")
 			return c.JSON(unAuthErr.Status, unAuthErr)
+		case errors.ErrUserInactive:
+			unAuthErr := errors.NewUnauthorizedError(err.Error())
+			return c.JSON(unAuthErr.Status, unAuthErr)
+		case errors.ErrLoginLocked:
+			tooManyErr := errors.NewTooManyRequestsError(err.Error())
+			return c.JSON(tooManyErr.Status, tooManyErr)
+		case errors.ErrEmailNotVerified, errors.ErrPasswordExpired:
+			forbiddenErr := errors.NewForbiddenError(err.Error())
+			return c.JSON(forbiddenErr.Status, forbiddenErr)
 		case errors.ErrCreateJwt:
 			serverErr := errors.NewInternalServerError("failed to create jwt token")
 			return c.JSON(serverErr.Status, serverErr)
@@ -68,6 +85,99 @@ func (ctr *auth) Login(c echo.Context) error {
 		}
 	}
 
+	if challenge != nil {
+		return c.JSON(http.StatusAccepted, challenge)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// swagger:route POST /v1/login/2fa Auth LoginTwoFactor
+// finish the login of a user with two factor
+// responses:
+//	200: LoginResp
+//	400: errorResponse
+//	401: errorResponse
+//	429: errorResponse
+//	500: errorResponse
+
+// LoginTwoFactor handles POST requests and logged in a user with the challenge token & a code
+func (ctr *auth) LoginTwoFactor(c echo.Context) error {
+	var req serializers.TwoFactorLoginReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	resp, err := ctr.authSvc.LoginTwoFactor(&req, GetClientInfo(c))
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidTwoFactorChallenge, errors.ErrInvalidTwoFactorCode, errors.ErrUserInactive:
+			unAuthErr := errors.NewUnauthorizedError(err.Error())
+			return c.JSON(unAuthErr.Status, unAuthErr)
+		case errors.ErrLoginLocked:
+			tooManyErr := errors.NewTooManyRequestsError(err.Error())
+			return c.JSON(tooManyErr.Status, tooManyErr)
+		case errors.ErrCreateJwt:
+			serverErr := errors.NewInternalServerError("failed to create jwt token")
+			return c.JSON(serverErr.Status, serverErr)
+		case errors.ErrStoreTokenUuid:
+			serverErr := errors.NewInternalServerError("failed to store jwt token uuid")
+			return c.JSON(serverErr.Status, serverErr)
+		default:
+			serverErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			return c.JSON(serverErr.Status, serverErr)
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// swagger:route POST /v1/login/2fa/enroll Auth EnrollTwoFactor
+// enroll a user whose role requires two factor on login, with the mailed enrollment code
+// responses:
+//	200: TwoFactorEnrollResponse
+//	400: errorResponse
+//	401: errorResponse
+//	429: errorResponse
+//	500: errorResponse
+
+// EnrollTwoFactor handles POST requests and returns the secret for the authenticator app once the
+// mailed enrollment code of the challenge is confirmed
+func (ctr *auth) EnrollTwoFactor(c echo.Context) error {
+	var req serializers.TwoFactorLoginEnrollReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	resp, err := ctr.authSvc.EnrollTwoFactor(&req, GetClientInfo(c))
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidTwoFactorChallenge, errors.ErrInvalidEnrollmentCode, errors.ErrUserInactive:
+			unAuthErr := errors.NewUnauthorizedError(err.Error())
+			return c.JSON(unAuthErr.Status, unAuthErr)
+		case errors.ErrLoginLocked:
+			tooManyErr := errors.NewTooManyRequestsError(err.Error())
+			return c.JSON(tooManyErr.Status, tooManyErr)
+		default:
+			serverErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			return c.JSON(serverErr.Status, serverErr)
+		}
+	}
+
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -110,13 +220,17 @@ func (ctr *auth) RefreshToken(c echo.Context) error {
 		return c.JSON(bodyErr.Status, bodyErr)
 	}
 
-	if res, err = ctr.authSvc.RefreshToken(token.RefreshToken); err != nil {
+	if res, err = ctr.authSvc.RefreshToken(token.RefreshToken, GetClientInfo(c)); err != nil {
 		switch err {
 		case errors.ErrParseJwt,
 			errors.ErrInvalidRefreshToken,
 			errors.ErrInvalidRefreshUuid:
 			unAuthErr := errors.NewUnauthorizedError("invalid refresh_token")
 			return c.JSON(unAuthErr.Status, unAuthErr)
+		case errors.ErrRefreshTokenReused,
+			errors.ErrUserInactive:
+			unAuthErr := errors.NewUnauthorizedError(err.Error())
+			return c.JSON(unAuthErr.Status, unAuthErr)
 		case errors.ErrCreateJwt:
 			serverErr := errors.NewInternalServerError("failed to create new jwt token")
 			return c.JSON(serverErr.Status, serverErr)
@@ -154,6 +268,87 @@ func (ctr *auth) VerifyToken(c echo.Context) error {
 	return c.JSON(http.StatusOK, res)
 }
 
+// swagger:route GET /v1/sessions Auth GetSessions
+// List the sessions of the logged-in user
+// responses:
+//	200: SessionsResponse
+//	401: errorResponse
+//	500: errorResponse
+
+// GetSessions handles GET requests and return where the logged-in user is logged in
+func (ctr *auth) GetSessions(c echo.Context) error {
+	user, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, err := ctr.authSvc.GetSessions(user)
+	if err != nil {
+		serverErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(serverErr.Status, serverErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /v1/sessions/{id} Auth RevokeSession
+// Log out of a session of the logged-in user
+// responses:
+//	200: genericSuccessResponse
+//	401: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// RevokeSession handles DELETE requests and log the user out of one session
+func (ctr *auth) RevokeSession(c echo.Context) error {
+	user, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := ctr.authSvc.RevokeSession(user, c.Param("id")); err != nil {
+		switch err {
+		case errors.ErrSessionNotFound:
+			notFoundErr := errors.NewNotFoundError(err.Error())
+			return c.JSON(notFoundErr.Status, notFoundErr)
+		default:
+			serverErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			return c.JSON(serverErr.Status, serverErr)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Successfully logged out of the session"})
+}
+
+// swagger:route DELETE /v1/sessions Auth RevokeSessions
+// Log the logged-in user out everywhere
+// responses:
+//	200: genericSuccessResponse
+//	401: errorResponse
+//	500: errorResponse
+
+// RevokeSessions handles DELETE requests and log the user out of every session, the current one too
+func (ctr *auth) RevokeSessions(c echo.Context) error {
+	user, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := ctr.authSvc.RevokeSessions(user); err != nil {
+		ctr.lc.Error(err.Error(), err)
+		serverErr := errors.NewInternalServerError("failed to logout")
+		return c.JSON(serverErr.Status, serverErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Successfully logged out everywhere"})
+}
+
 func AccessTokenFromHeader(c echo.Context) (string, error) {
 	header := "Authorization"
 	authScheme := "Bearer"
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"next-oms/app/serializers"
 	"next-oms/infra/errors"
+	"strconv"
 )
 
 func GetUserFromContext(c echo.Context) (*serializers.LoggedInUser, error) {
@@ -14,3 +15,21 @@ func GetUserFromContext(c echo.Context) (*serializers.LoggedInUser, error) {
 
 	return user, nil
 }
+
+// GetClientInfo returns the ip & user agent of the client which sent the request
+func GetClientInfo(c echo.Context) *serializers.ClientInfo {
+	return &serializers.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+}
+
+// GetIDParam parses a numeric id from the named path param
+func GetIDParam(c echo.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.ErrInvalidIDParam
+	}
+
+	return uint(id), nil
+}
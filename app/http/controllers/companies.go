@@ -0,0 +1,387 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type companies struct {
+	lc   logger.LogClient
+	cSvc svc.ICompanies
+	uSvc svc.IUsers
+}
+
+// NewCompaniesController will initialize the controllers
+func NewCompaniesController(grp interface{}, lc logger.LogClient, cSvc svc.ICompanies, uSvc svc.IUsers) {
+	cc := &companies{
+		lc:   lc,
+		cSvc: cSvc,
+		uSvc: uSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionCompanyManage)
+	manageUsers := middlewares.RequirePermission(consts.PermissionCompanyUsersManage)
+
+	g.POST("/v1/businesses", cc.CreateBusiness, manage)
+	g.GET("/v1/businesses", cc.GetBusinesses, manage)
+	g.GET("/v1/businesses/:id", cc.GetBusiness, manage)
+	g.PUT("/v1/businesses/:id", cc.UpdateBusiness, manage)
+	g.DELETE("/v1/businesses/:id", cc.DeleteBusiness, manage)
+
+	g.POST("/v1/companies", cc.CreateCompany, manage)
+	g.GET("/v1/companies", cc.GetCompanies, manage)
+	g.GET("/v1/companies/:id", cc.GetCompany, manage)
+	g.PUT("/v1/companies/:id", cc.UpdateCompany, manage)
+	g.DELETE("/v1/companies/:id", cc.DeleteCompany, manage)
+
+	g.PUT("/v1/companies/:id/users/:user_id", cc.AssignUser, manage)
+	g.DELETE("/v1/companies/:id/users/:user_id", cc.UnassignUser, manage)
+
+	g.GET("/v1/companies/:id/users", cc.GetUsers, manageUsers)
+	g.POST("/v1/companies/:id/users/invite", cc.InviteUser, manageUsers)
+	g.PATCH("/v1/companies/:id/users/:user_id/activate", cc.ActivateUser, manageUsers)
+	g.PATCH("/v1/companies/:id/users/:user_id/deactivate", cc.DeactivateUser, manageUsers)
+}
+
+// CreateBusiness handles POST requests and create a new business
+func (ctr *companies) CreateBusiness(c echo.Context) error {
+	var req serializers.BusinessReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.cSvc.CreateBusiness(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetBusinesses handles GET requests and return all the businesses
+func (ctr *companies) GetBusinesses(c echo.Context) error {
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.cSvc.GetBusinesses(listParams)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetBusiness handles GET requests and return a single business
+func (ctr *companies) GetBusiness(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("business id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.cSvc.GetBusiness(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateBusiness handles PUT requests and update a business
+func (ctr *companies) UpdateBusiness(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("business id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.BusinessReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.cSvc.UpdateBusiness(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("business")})
+}
+
+// DeleteBusiness handles DELETE requests and delete a business
+func (ctr *companies) DeleteBusiness(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("business id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.cSvc.DeleteBusiness(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("business")})
+}
+
+// CreateCompany handles POST requests and create a new company
+func (ctr *companies) CreateCompany(c echo.Context) error {
+	var req serializers.CompanyReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.cSvc.CreateCompany(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetCompanies handles GET requests and return all the companies
+func (ctr *companies) GetCompanies(c echo.Context) error {
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.cSvc.GetCompanies(listParams)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetCompany handles GET requests and return a single company
+func (ctr *companies) GetCompany(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.cSvc.GetCompany(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateCompany handles PUT requests and update a company
+func (ctr *companies) UpdateCompany(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.CompanyReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.cSvc.UpdateCompany(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("company")})
+}
+
+// DeleteCompany handles DELETE requests and delete a company
+func (ctr *companies) DeleteCompany(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.cSvc.DeleteCompany(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("company")})
+}
+
+// AssignUser handles PUT requests and move a user to the company
+func (ctr *companies) AssignUser(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	userID, err := GetIDParam(c, "user_id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("user id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if assignErr := ctr.uSvc.AssignCompany(userID, &id); assignErr != nil {
+		return c.JSON(assignErr.Status, assignErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user company")})
+}
+
+// UnassignUser handles DELETE requests and take a user out of the company
+func (ctr *companies) UnassignUser(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	userID, err := GetIDParam(c, "user_id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("user id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	user, getErr := ctr.uSvc.GetUserById(userID)
+	if getErr != nil || user.CompanyID == nil || *user.CompanyID != id {
+		restErr := errors.NewNotFoundError("user not found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if assignErr := ctr.uSvc.AssignCompany(userID, nil); assignErr != nil {
+		return c.JSON(assignErr.Status, assignErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user company")})
+}
+
+// GetUsers handles GET requests and return the users of the company
+func (ctr *companies) GetUsers(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.uSvc.GetCompanyUsers(uint(loggedInUser.ID), id, listParams)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// InviteUser handles POST requests and add a new user to the company
+func (ctr *companies) InviteUser(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.InviteUserReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, inviteErr := ctr.uSvc.InviteUser(uint(loggedInUser.ID), id, &req)
+	if inviteErr != nil {
+		return c.JSON(inviteErr.Status, inviteErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// ActivateUser handles PATCH requests and let a user of the company log in again
+func (ctr *companies) ActivateUser(c echo.Context) error {
+	return ctr.setUserActive(c, true)
+}
+
+// DeactivateUser handles PATCH requests and stop a user of the company from logging in
+func (ctr *companies) DeactivateUser(c echo.Context) error {
+	return ctr.setUserActive(c, false)
+}
+
+func (ctr *companies) setUserActive(c echo.Context, active bool) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("company id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	userID, err := GetIDParam(c, "user_id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("user id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if setErr := ctr.uSvc.SetUserActive(uint(loggedInUser.ID), id, userID, active); setErr != nil {
+		return c.JSON(setErr.Status, setErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user status")})
+}
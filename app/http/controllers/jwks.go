@@ -0,0 +1,14 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/infra/jwtkeys"
+)
+
+// JWKS handles GET requests and return the public keys the access tokens can be verified with,
+// other services fetch it so they don't need the signing secret
+func JWKS(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, jwtkeys.JWKS())
+}
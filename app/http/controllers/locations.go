@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type locations struct {
+	lc     logger.LogClient
+	locSvc svc.ILocations
+}
+
+// NewLocationsController will initialize the controllers
+func NewLocationsController(grp interface{}, lc logger.LogClient, locSvc svc.ILocations) {
+	lcc := &locations{
+		lc:     lc,
+		locSvc: locSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionLocationManage)
+
+	g.POST("/v1/cities", lcc.CreateCity, manage)
+	g.GET("/v1/cities", lcc.GetCities)
+	g.GET("/v1/cities/:id", lcc.GetCity)
+	g.PUT("/v1/cities/:id", lcc.UpdateCity, manage)
+	g.DELETE("/v1/cities/:id", lcc.DeleteCity, manage)
+	g.GET("/v1/cities/:id/zones", lcc.GetZones)
+
+	g.POST("/v1/zones", lcc.CreateZone, manage)
+	g.GET("/v1/zones/:id", lcc.GetZone)
+	g.PUT("/v1/zones/:id", lcc.UpdateZone, manage)
+	g.DELETE("/v1/zones/:id", lcc.DeleteZone, manage)
+	g.GET("/v1/zones/:id/areas", lcc.GetAreas)
+
+	g.POST("/v1/areas", lcc.CreateArea, manage)
+	g.GET("/v1/areas/:id", lcc.GetArea)
+	g.PUT("/v1/areas/:id", lcc.UpdateArea, manage)
+	g.DELETE("/v1/areas/:id", lcc.DeleteArea, manage)
+}
+
+// CreateCity handles POST requests and create a new city
+func (ctr *locations) CreateCity(c echo.Context) error {
+	var req serializers.CityReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.locSvc.CreateCity(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetCities handles GET requests and return all the cities
+func (ctr *locations) GetCities(c echo.Context) error {
+	result, getErr := ctr.locSvc.GetCities()
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetCity handles GET requests and return a single city
+func (ctr *locations) GetCity(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("city id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.locSvc.GetCity(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateCity handles PUT requests and update a city
+func (ctr *locations) UpdateCity(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("city id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.CityReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.locSvc.UpdateCity(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("city")})
+}
+
+// DeleteCity handles DELETE requests and delete a city
+func (ctr *locations) DeleteCity(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("city id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.locSvc.DeleteCity(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("city")})
+}
+
+// CreateZone handles POST requests and create a new zone
+func (ctr *locations) CreateZone(c echo.Context) error {
+	var req serializers.ZoneReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.locSvc.CreateZone(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetZones handles GET requests and return all the zones of a city
+func (ctr *locations) GetZones(c echo.Context) error {
+	cityID, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("city id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.locSvc.GetZones(cityID)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetZone handles GET requests and return a single zone
+func (ctr *locations) GetZone(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("zone id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.locSvc.GetZone(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateZone handles PUT requests and update a zone
+func (ctr *locations) UpdateZone(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("zone id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.ZoneReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.locSvc.UpdateZone(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("zone")})
+}
+
+// DeleteZone handles DELETE requests and delete a zone
+func (ctr *locations) DeleteZone(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("zone id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.locSvc.DeleteZone(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("zone")})
+}
+
+// CreateArea handles POST requests and create a new area
+func (ctr *locations) CreateArea(c echo.Context) error {
+	var req serializers.AreaReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.locSvc.CreateArea(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetAreas handles GET requests and return all the areas of a zone
+func (ctr *locations) GetAreas(c echo.Context) error {
+	zoneID, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("zone id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.locSvc.GetAreas(zoneID)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetArea handles GET requests and return a single area
+func (ctr *locations) GetArea(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("area id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.locSvc.GetArea(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateArea handles PUT requests and update a area
+func (ctr *locations) UpdateArea(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("area id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.AreaReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.locSvc.UpdateArea(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("area")})
+}
+
+// DeleteArea handles DELETE requests and delete a area
+func (ctr *locations) DeleteArea(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("area id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.locSvc.DeleteArea(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("area")})
+}
@@ -1,12 +1,18 @@
 package controllers
 
 import (
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"next-oms/app/http/middlewares"
 	"next-oms/app/serializers"
 	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
 	"next-oms/infra/errors"
 	"next-oms/infra/logger"
+	"strconv"
+	"strings"
 )
 
 type orders struct {
@@ -23,9 +29,15 @@ func NewOrdersController(grp interface{}, lc logger.LogClient, oSvc svc.IOrders)
 
 	g := grp.(*echo.Group)
 
-	g.POST("/v1/orders", oc.Create)
+	g.POST("/v1/orders", oc.Create, middlewares.Idempotency())
+	g.POST("/v1/orders/quote", oc.Quote)
+	g.POST("/v1/orders/bulk", oc.BulkCreate, middlewares.Idempotency())
 	g.GET("/v1/orders/all", oc.GetOrders)
-	g.PUT("/v1/orders/:con_id/cancel", oc.CancelOrder)
+	g.GET("/v1/orders/:con_id", oc.GetOrder)
+	g.GET("/v1/orders/by-merchant/:merchant_order_id", oc.GetOrderByMerchantOrderID)
+	g.PUT("/v1/orders/:con_id/cancel", oc.CancelOrder, middlewares.RequirePermission(consts.PermissionOrderCancel))
+	g.PATCH("/v1/orders/:con_id/status", oc.UpdateOrderStatus, middlewares.RequirePermission(consts.PermissionOrderStatusUpdate))
+	g.GET("/v1/orders/:con_id/history", oc.GetOrderHistory)
 }
 
 // swagger:route POST /v1/orders OrderReq CreateOrder
@@ -38,6 +50,13 @@ func NewOrdersController(grp interface{}, lc logger.LogClient, oSvc svc.IOrders)
 
 // Create handles POST requests and create a new order
 func (ctr *orders) Create(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
 	var order serializers.OrderReq
 
 	if err := c.Bind(&order); err != nil {
@@ -50,7 +69,7 @@ func (ctr *orders) Create(c echo.Context) error {
 		return c.JSON(restErr.Status, restErr)
 	}
 
-	resp, saveErr := ctr.oSvc.CreateOrder(&order)
+	resp, saveErr := ctr.oSvc.CreateOrder(uint(loggedInUser.ID), &order)
 	if saveErr != nil {
 		return c.JSON(saveErr.Status, saveErr)
 	}
@@ -63,13 +82,139 @@ func (ctr *orders) Create(c echo.Context) error {
 	})
 }
 
+// swagger:route POST /v1/orders/bulk OrderReq BulkCreateOrders
+// Create orders in bulk from a json array or a multipart csv file
+// consumes:
+//	- application/json
+//	- multipart/form-data
+// responses:
+//	200: BulkOrderResponse
+//	400: errorResponse
+//	500: errorResponse
+
+// BulkCreate handles POST requests and create orders in bulk, each row is validated on its own
+func (ctr *orders) BulkCreate(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var rows []*serializers.BulkOrderRow
+
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		rows, err = ordersFromCSV(c)
+		if err != nil {
+			restErr := errors.NewBadRequestError(err.Error())
+			return c.JSON(restErr.Status, restErr)
+		}
+	} else {
+		var orders []*serializers.OrderReq
+		if err := c.Bind(&orders); err != nil {
+			restErr := errors.NewBadRequestError("invalid json body, expected an array of orders")
+			return c.JSON(restErr.Status, restErr)
+		}
+
+		for _, order := range orders {
+			// a null element is kept as an empty order, so it gets rejected by the validation
+			if order == nil {
+				order = &serializers.OrderReq{}
+			}
+			rows = append(rows, &serializers.BulkOrderRow{Order: order})
+		}
+	}
+
+	if len(rows) == 0 {
+		restErr := errors.NewBadRequestError("no order found in the request")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if len(rows) > consts.BulkOrderMaxRows {
+		restErr := errors.NewBadRequestError(fmt.Sprintf("at most %d orders can be created at once", consts.BulkOrderMaxRows))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	resp := ctr.oSvc.CreateOrders(uint(loggedInUser.ID), rows)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("%d of %d orders created", resp.Created, resp.Total),
+		"type":    "success",
+		"code":    200,
+		"data":    resp,
+	})
+}
+
+// ordersFromCSV reads the orders of the uploaded csv file
+func ordersFromCSV(c echo.Context) ([]*serializers.BulkOrderRow, error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, errors.NewError("csv file is required in the file field")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.NewError("failed to open csv file")
+	}
+	defer file.Close()
+
+	return serializers.ParseOrdersCSV(file)
+}
+
+// swagger:route POST /v1/orders/quote OrderReq QuoteOrder
+// Get the fee breakdown of an order without creating it
+// responses:
+//	200: OrderQuoteResponse
+//	400: errorResponse
+//	500: errorResponse
+
+// Quote handles POST requests and return the fee breakdown of an order
+func (ctr *orders) Quote(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var order serializers.OrderReq
+
+	if err := c.Bind(&order); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := order.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	resp, quoteErr := ctr.oSvc.QuoteOrder(uint(loggedInUser.ID), &order)
+	if quoteErr != nil {
+		return c.JSON(quoteErr.Status, quoteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order successfully quoted.",
+		"type":    "success",
+		"code":    200,
+		"data":    resp,
+	})
+}
+
 // GetOrders handles GET requests and all the orders
 func (ctr *orders) GetOrders(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
 	listParams := &serializers.ListFilters{}
 	listParams.GenerateFilters(c.QueryParams())
 	listParams.BasePath = c.Request().URL.Path
 
-	result, saveErr := ctr.oSvc.GetOrders(listParams)
+	result, saveErr := ctr.oSvc.GetOrders(uint(loggedInUser.ID), listParams)
 	if saveErr != nil {
 		return c.JSON(saveErr.Status, saveErr)
 	}
@@ -82,15 +227,152 @@ func (ctr *orders) GetOrders(c echo.Context) error {
 	})
 }
 
+// swagger:route GET /v1/orders/{con_id} Order GetOrder
+// Get an order by consignment id
+// responses:
+//	200: OrderResponse
+//	400: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// GetOrder handles GET requests and return a single order by consignment id
+func (ctr *orders) GetOrder(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	conID := c.Param("con_id")
+	if conID == "" {
+		restErr := errors.NewBadRequestError("order_consignment_id is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.oSvc.GetOrder(uint(loggedInUser.ID), conID)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order successfully fetched.",
+		"type":    "success",
+		"code":    200,
+		"data":    result,
+	})
+}
+
+// swagger:route GET /v1/orders/by-merchant/{merchant_order_id} Order GetOrderByMerchantOrderID
+// Get an order by merchant order id, the id is unique within the store of the store_id query param
+// responses:
+//	200: OrderResponse
+//	400: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// GetOrderByMerchantOrderID handles GET requests and return a single order by merchant order id
+func (ctr *orders) GetOrderByMerchantOrderID(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	merchantOrderID := c.Param("merchant_order_id")
+	if merchantOrderID == "" {
+		restErr := errors.NewBadRequestError("merchant_order_id is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	storeID, err := strconv.Atoi(c.QueryParam("store_id"))
+	if err != nil || storeID <= 0 {
+		restErr := errors.NewBadRequestError("store_id query param is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.oSvc.GetOrderByMerchantOrderID(uint(loggedInUser.ID), storeID, merchantOrderID)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order successfully fetched.",
+		"type":    "success",
+		"code":    200,
+		"data":    result,
+	})
+}
+
+// swagger:route PATCH /v1/orders/{con_id}/status Order UpdateOrderStatus
+// Move an order to the next status of its lifecycle
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// UpdateOrderStatus handles PATCH requests and change the status of an order
+func (ctr *orders) UpdateOrderStatus(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	conID := c.Param("con_id")
+	if conID == "" {
+		restErr := errors.NewBadRequestError("order_consignment_id is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.OrderStatusReq
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.oSvc.UpdateOrderStatus(conID, uint(loggedInUser.ID), &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": msgutil.EntityUpdateSuccessMsg("order status"),
+		"type":    "success",
+		"code":    200,
+	})
+}
+
 // CancelOrder handles PUT requests and cancel an order
 func (ctr *orders) CancelOrder(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
 	conID := c.Param("con_id")
 	if conID == "" {
 		restErr := errors.NewBadRequestError("order_consignment_id is required")
 		return c.JSON(restErr.Status, restErr)
 	}
 
-	cancelErr := ctr.oSvc.CancelOrder(conID)
+	// the reason is optional, so an empty body is accepted
+	var req serializers.CancelOrderReq
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	cancelErr := ctr.oSvc.CancelOrder(conID, uint(loggedInUser.ID), &req)
 	if cancelErr != nil {
 		return c.JSON(cancelErr.Status, cancelErr)
 	}
@@ -102,3 +384,38 @@ func (ctr *orders) CancelOrder(c echo.Context) error {
 	})
 
 }
+
+// swagger:route GET /v1/orders/{con_id}/history Order GetOrderHistory
+// Get the status timeline of an order
+// responses:
+//	200: OrderHistoryResponse
+//	400: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// GetOrderHistory handles GET requests and return the status changes of an order
+func (ctr *orders) GetOrderHistory(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	conID := c.Param("con_id")
+	if conID == "" {
+		restErr := errors.NewBadRequestError("order_consignment_id is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.oSvc.GetOrderHistory(uint(loggedInUser.ID), conID)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order history successfully fetched.",
+		"type":    "success",
+		"code":    200,
+		"data":    result,
+	})
+}
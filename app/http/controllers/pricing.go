@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type pricing struct {
+	lc   logger.LogClient
+	pSvc svc.IPricing
+}
+
+// NewPricingController will initialize the controllers
+func NewPricingController(grp interface{}, lc logger.LogClient, pSvc svc.IPricing) {
+	pc := &pricing{
+		lc:   lc,
+		pSvc: pSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionPricingManage)
+
+	g.POST("/v1/rate-cards", pc.CreateRateCard, manage)
+	g.GET("/v1/rate-cards", pc.GetRateCards, manage)
+	g.GET("/v1/rate-cards/:id", pc.GetRateCard, manage)
+	g.PUT("/v1/rate-cards/:id", pc.UpdateRateCard, manage)
+	g.DELETE("/v1/rate-cards/:id", pc.DeleteRateCard, manage)
+}
+
+// swagger:route POST /v1/rate-cards RateCard CreateRateCard
+// Create a new rate card
+// responses:
+//	201: RateCardResponse
+//	400: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// CreateRateCard handles POST requests and create a new rate card
+func (ctr *pricing) CreateRateCard(c echo.Context) error {
+	var req serializers.RateCardReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.pSvc.CreateRateCard(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// swagger:route GET /v1/rate-cards RateCard GetRateCards
+// List all the rate cards
+// responses:
+//	200: RateCardsResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// GetRateCards handles GET requests and return all the rate cards
+func (ctr *pricing) GetRateCards(c echo.Context) error {
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.pSvc.GetRateCards(listParams)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /v1/rate-cards/{id} RateCard GetRateCard
+// Get a rate card with all of its charges
+// responses:
+//	200: RateCardResponse
+//	400: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// GetRateCard handles GET requests and return a single rate card
+func (ctr *pricing) GetRateCard(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("rate card id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.pSvc.GetRateCard(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route PUT /v1/rate-cards/{id} RateCard UpdateRateCard
+// Add a new version of a rate card, the previous versions keep the charges they priced orders with
+// responses:
+//	200: RateCardResponse
+//	400: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// UpdateRateCard handles PUT requests and replace a rate card with a new version
+func (ctr *pricing) UpdateRateCard(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("rate card id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.RateCardReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, updateErr := ctr.pSvc.UpdateRateCard(id, &req)
+	if updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /v1/rate-cards/{id} RateCard DeleteRateCard
+// Delete a rate card with all of its versions
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	403: errorResponse
+//	404: errorResponse
+//	500: errorResponse
+
+// DeleteRateCard handles DELETE requests and delete a rate card
+func (ctr *pricing) DeleteRateCard(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("rate card id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.pSvc.DeleteRateCard(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("rate card")})
+}
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/http/middlewares"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type roles struct {
+	lc   logger.LogClient
+	rSvc svc.IRoles
+}
+
+// NewRolesController will initialize the controllers
+func NewRolesController(grp interface{}, lc logger.LogClient, rSvc svc.IRoles) {
+	rc := &roles{
+		lc:   lc,
+		rSvc: rSvc,
+	}
+
+	g := grp.(*echo.Group)
+	manage := middlewares.RequirePermission(consts.PermissionRoleManage)
+
+	g.POST("/v1/roles", rc.CreateRole, manage)
+	g.GET("/v1/roles", rc.GetRoles, manage)
+	g.GET("/v1/roles/:id", rc.GetRole, manage)
+	g.PUT("/v1/roles/:id", rc.UpdateRole, manage)
+	g.DELETE("/v1/roles/:id", rc.DeleteRole, manage)
+	g.PUT("/v1/roles/:id/permissions", rc.SetRolePermissions, manage)
+
+	g.POST("/v1/permissions", rc.CreatePermission, manage)
+	g.GET("/v1/permissions", rc.GetPermissions, manage)
+	g.GET("/v1/permissions/:id", rc.GetPermission, manage)
+	g.PUT("/v1/permissions/:id", rc.UpdatePermission, manage)
+	g.DELETE("/v1/permissions/:id", rc.DeletePermission, manage)
+}
+
+// CreateRole handles POST requests and create a new role
+func (ctr *roles) CreateRole(c echo.Context) error {
+	var req serializers.RoleReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.rSvc.CreateRole(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetRoles handles GET requests and return all the roles
+func (ctr *roles) GetRoles(c echo.Context) error {
+	result, getErr := ctr.rSvc.GetRoles()
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetRole handles GET requests and return a single role along with its permissions
+func (ctr *roles) GetRole(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("role id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.rSvc.GetRole(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateRole handles PUT requests and update a role
+func (ctr *roles) UpdateRole(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("role id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.RoleReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.rSvc.UpdateRole(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("role")})
+}
+
+// DeleteRole handles DELETE requests and delete a role
+func (ctr *roles) DeleteRole(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("role id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.rSvc.DeleteRole(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("role")})
+}
+
+// SetRolePermissions handles PUT requests and replace the permissions of a role
+func (ctr *roles) SetRolePermissions(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("role id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.RolePermissionsReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if setErr := ctr.rSvc.SetRolePermissions(id, &req); setErr != nil {
+		return c.JSON(setErr.Status, setErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("role permissions")})
+}
+
+// CreatePermission handles POST requests and create a new permission
+func (ctr *roles) CreatePermission(c echo.Context) error {
+	var req serializers.PermissionReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.rSvc.CreatePermission(&req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetPermissions handles GET requests and return all the permissions
+func (ctr *roles) GetPermissions(c echo.Context) error {
+	result, getErr := ctr.rSvc.GetPermissions()
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetPermission handles GET requests and return a single permission
+func (ctr *roles) GetPermission(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("permission id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.rSvc.GetPermission(id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdatePermission handles PUT requests and update a permission
+func (ctr *roles) UpdatePermission(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("permission id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.PermissionReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.rSvc.UpdatePermission(id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("permission")})
+}
+
+// DeletePermission handles DELETE requests and delete a permission
+func (ctr *roles) DeletePermission(c echo.Context) error {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("permission id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.rSvc.DeletePermission(id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("permission")})
+}
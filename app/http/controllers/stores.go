@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type stores struct {
+	lc    logger.LogClient
+	stSvc svc.IStores
+}
+
+// NewStoresController will initialize the controllers
+func NewStoresController(grp interface{}, lc logger.LogClient, stSvc svc.IStores) {
+	sc := &stores{
+		lc:    lc,
+		stSvc: stSvc,
+	}
+
+	g := grp.(*echo.Group)
+
+	g.POST("/v1/stores", sc.Create)
+	g.GET("/v1/stores", sc.GetStores)
+	g.GET("/v1/stores/:id", sc.GetStore)
+	g.PUT("/v1/stores/:id", sc.Update)
+	g.DELETE("/v1/stores/:id", sc.Delete)
+}
+
+// Create handles POST requests and create a new store for the logged-in user
+func (ctr *stores) Create(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.StoreReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, saveErr := ctr.stSvc.CreateStore(uint(loggedInUser.ID), &req)
+	if saveErr != nil {
+		return c.JSON(saveErr.Status, saveErr)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetStores handles GET requests and return the stores of the logged-in user
+func (ctr *stores) GetStores(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	listParams := &serializers.ListFilters{}
+	listParams.GenerateFilters(c.QueryParams())
+	listParams.BasePath = c.Request().URL.Path
+
+	result, getErr := ctr.stSvc.GetStores(uint(loggedInUser.ID), listParams)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetStore handles GET requests and return a single store of the logged-in user
+func (ctr *stores) GetStore(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("store id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, getErr := ctr.stSvc.GetStore(uint(loggedInUser.ID), id)
+	if getErr != nil {
+		return c.JSON(getErr.Status, getErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Update handles PUT requests and update a store of the logged-in user
+func (ctr *stores) Update(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("store id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.StoreReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if updateErr := ctr.stSvc.UpdateStore(uint(loggedInUser.ID), id, &req); updateErr != nil {
+		return c.JSON(updateErr.Status, updateErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("store")})
+}
+
+// Delete handles DELETE requests and delete a store of the logged-in user
+func (ctr *stores) Delete(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		restErr := errors.NewBadRequestError(msgutil.EntityGenericInvalidMsg("store id"))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if deleteErr := ctr.stSvc.DeleteStore(uint(loggedInUser.ID), id); deleteErr != nil {
+		return c.JSON(deleteErr.Status, deleteErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityDeleteSuccessMsg("store")})
+}
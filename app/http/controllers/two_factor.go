@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type twoFactor struct {
+	lc    logger.LogClient
+	tfSvc svc.ITwoFactor
+}
+
+// NewTwoFactorController will initialize the controllers
+func NewTwoFactorController(grp interface{}, lc logger.LogClient, tfSvc svc.ITwoFactor) {
+	tc := &twoFactor{
+		lc:    lc,
+		tfSvc: tfSvc,
+	}
+
+	g := grp.(*echo.Group)
+
+	g.POST("/v1/2fa/enroll", tc.Enroll)
+	g.POST("/v1/2fa/confirm", tc.Confirm)
+	g.POST("/v1/2fa/disable", tc.Disable)
+	g.POST("/v1/2fa/backup-codes", tc.RegenerateBackupCodes)
+}
+
+// swagger:route POST /v1/2fa/enroll TwoFactor EnrollTwoFactor
+// Start the two factor enrollment of the logged-in user
+// responses:
+//	200: TwoFactorEnrollResponse
+//	401: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// Enroll handles POST requests and return the secret for the authenticator app
+func (ctr *twoFactor) Enroll(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, enrollErr := ctr.tfSvc.Enroll(uint(loggedInUser.ID))
+	if enrollErr != nil {
+		return c.JSON(enrollErr.Status, enrollErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /v1/2fa/confirm TwoFactor ConfirmTwoFactor
+// Turn two factor on with the first code of the authenticator app
+// responses:
+//	200: BackupCodesResponse
+//	400: errorResponse
+//	401: errorResponse
+//	409: errorResponse
+//	500: errorResponse
+
+// Confirm handles POST requests and turn two factor on
+func (ctr *twoFactor) Confirm(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.TwoFactorCodeReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, confirmErr := ctr.tfSvc.Confirm(uint(loggedInUser.ID), &req)
+	if confirmErr != nil {
+		return c.JSON(confirmErr.Status, confirmErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /v1/2fa/disable TwoFactor DisableTwoFactor
+// Turn two factor off for the logged-in user
+// responses:
+//	200: genericSuccessResponse
+//	400: errorResponse
+//	401: errorResponse
+//	403: errorResponse
+//	500: errorResponse
+
+// Disable handles POST requests and turn two factor off
+func (ctr *twoFactor) Disable(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.TwoFactorDisableReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if disableErr := ctr.tfSvc.Disable(uint(loggedInUser.ID), &req); disableErr != nil {
+		return c.JSON(disableErr.Status, disableErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "two factor authentication disabled"})
+}
+
+// swagger:route POST /v1/2fa/backup-codes TwoFactor RegenerateBackupCodes
+// Replace the backup codes of the logged-in user
+// responses:
+//	200: BackupCodesResponse
+//	400: errorResponse
+//	401: errorResponse
+//	500: errorResponse
+
+// RegenerateBackupCodes handles POST requests and return new backup codes
+func (ctr *twoFactor) RegenerateBackupCodes(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	var req serializers.TwoFactorCodeReq
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, genErr := ctr.tfSvc.RegenerateBackupCodes(uint(loggedInUser.ID), &req)
+	if genErr != nil {
+		return c.JSON(genErr.Status, genErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -1,17 +1,19 @@
 package controllers
 
 import (
+	goerrors "errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
+	"io"
 	"net/http"
 	"next-oms/app/domain"
 	"next-oms/app/serializers"
 	"next-oms/app/svc"
 	"next-oms/app/utils/methodsutil"
 	"next-oms/app/utils/msgutil"
+	"next-oms/infra/config"
 	"next-oms/infra/errors"
 	"next-oms/infra/logger"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type users struct {
@@ -29,7 +31,10 @@ func NewUsersController(grp interface{}, lc logger.LogClient, uSvc svc.IUsers) {
 	g := grp.(*echo.Group)
 
 	g.POST("/v1/users/signup", uc.Create)
+	g.POST("/v1/users/verify", uc.VerifyEmail)
+	g.POST("/v1/users/verify/resend", uc.ResendVerification)
 	g.PATCH("/v1/user", uc.Update)
+	g.POST("/v1/user/avatar", uc.UploadAvatar)
 	g.POST("/v1/password/change", uc.ChangePassword)
 	g.POST("/v1/password/forgot", uc.ForgotPassword)
 	g.POST("/v1/password/verifyreset", uc.VerifyResetPassword)
@@ -46,15 +51,25 @@ func NewUsersController(grp interface{}, lc logger.LogClient, uSvc svc.IUsers) {
 
 // Create handles POST requests and create a new sales user
 func (ctr *users) Create(c echo.Context) error {
-	var user domain.User
+	var req serializers.UserReq
 
-	if err := c.Bind(&user); err != nil {
+	if err := c.Bind(&req); err != nil {
 		restErr := errors.NewBadRequestError("invalid json body")
 		return c.JSON(restErr.Status, restErr)
 	}
 
-	hashedPass, _ := bcrypt.GenerateFromPassword([]byte(*user.Password), 8)
-	*user.Password = string(hashedPass)
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	// only the profile is taken from the body, the role & company are granted by admins
+	var user domain.User
+	if err := methodsutil.StructToStruct(req, &user); err != nil {
+		ctr.lc.Error(msgutil.EntityStructToStructFailedMsg("set signup request to user"), err)
+		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(restErr.Status, restErr)
+	}
 
 	result, saveErr := ctr.uSvc.CreateUser(user)
 	if saveErr != nil {
@@ -69,6 +84,55 @@ func (ctr *users) Create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, resp)
 }
 
+func (ctr *users) VerifyEmail(c echo.Context) error {
+	req := &serializers.VerifyEmailReq{}
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := ctr.uSvc.VerifyEmail(req.Token); err != nil {
+		switch err {
+		case errors.ErrParseJwt,
+			errors.ErrInvalidVerifyToken:
+			restErr := errors.NewBadRequestError("invalid or expired verification token")
+			return c.JSON(restErr.Status, restErr)
+		default:
+			restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			return c.JSON(restErr.Status, restErr)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "email verified"})
+}
+
+func (ctr *users) ResendVerification(c echo.Context) error {
+	req := &serializers.ResendVerificationReq{}
+
+	if err := c.Bind(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := req.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if err := ctr.uSvc.ResendVerification(req.Email); err != nil {
+		restErr := errors.NewInternalServerError("failed to send verification email")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"message": "Verification link sent to email"})
+}
+
 func (ctr *users) Update(c echo.Context) error {
 	loggedInUser, err := GetUserFromContext(c)
 	if err != nil {
@@ -83,6 +147,11 @@ func (ctr *users) Update(c echo.Context) error {
 		return c.JSON(restErr.Status, restErr)
 	}
 
+	if err := user.Validate(); err != nil {
+		restErr := errors.NewBadRequestError(err.Error())
+		return c.JSON(restErr.Status, restErr)
+	}
+
 	updateErr := ctr.uSvc.UpdateUser(uint(loggedInUser.ID), user)
 	if updateErr != nil {
 		return c.JSON(updateErr.Status, updateErr)
@@ -91,6 +160,71 @@ func (ctr *users) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{"message": msgutil.EntityUpdateSuccessMsg("user")})
 }
 
+// swagger:route POST /v1/user/avatar User UploadAvatar
+// Upload a jpeg, png or gif image as the avatar of the logged-in user, it's resized to thumbnails
+// consumes:
+//	- multipart/form-data
+// responses:
+//	200: AvatarResponse
+//	400: errorResponse
+//	401: errorResponse
+//	413: errorResponse
+//	415: errorResponse
+//	500: errorResponse
+
+// UploadAvatar handles POST requests and set the avatar of the logged-in user
+func (ctr *users) UploadAvatar(c echo.Context) error {
+	loggedInUser, err := GetUserFromContext(c)
+	if err != nil {
+		ctr.lc.Error(err.Error(), err)
+		restErr := errors.NewUnauthorizedError("no logged-in user found")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	maxSize := config.Avatar().MaxSize
+
+	// the multipart body is cut off a bit above the max size, the rest of the form takes some room
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if goerrors.As(err, &maxBytesErr) {
+			restErr := errors.NewPayloadTooLargeError(fmt.Sprintf("avatar can be at most %d bytes", maxSize))
+			return c.JSON(restErr.Status, restErr)
+		}
+		restErr := errors.NewBadRequestError("avatar file is required")
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if fileHeader.Size > maxSize {
+		restErr := errors.NewPayloadTooLargeError(fmt.Sprintf("avatar can be at most %d bytes", maxSize))
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctr.lc.Error("error occurred when opening uploaded avatar", err)
+		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(restErr.Status, restErr)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		ctr.lc.Error("error occurred when reading uploaded avatar", err)
+		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	result, uploadErr := ctr.uSvc.UploadAvatar(uint(loggedInUser.ID), data)
+	if uploadErr != nil {
+		return c.JSON(uploadErr.Status, uploadErr)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 func (ctr *users) ChangePassword(c echo.Context) error {
 	loggedInUser, err := GetUserFromContext(c)
 	if err != nil {
@@ -111,7 +245,11 @@ func (ctr *users) ChangePassword(c echo.Context) error {
 		restErr := errors.NewBadRequestError("password can't be same as old one")
 		return c.JSON(restErr.Status, restErr)
 	}
-	if err := ctr.uSvc.ChangePassword(loggedInUser.ID, body); err != nil {
+	if err := ctr.uSvc.ChangePassword(loggedInUser, body); err != nil {
+		if policyErr, ok := err.(*errors.PasswordPolicyError); ok {
+			restErr := errors.NewPasswordPolicyError(policyErr.Violations)
+			return c.JSON(restErr.Status, restErr)
+		}
 		switch err {
 		case errors.ErrInvalidPassword:
 			restErr := errors.NewBadRequestError("old password didn't match")
@@ -187,16 +325,15 @@ func (ctr *users) ResetPassword(c echo.Context) error {
 		return c.JSON(restErr.Status, restErr)
 	}
 
-	verifyReq := &serializers.VerifyResetPasswordReq{
-		Token: req.Token,
-		ID:    req.ID,
-	}
-
-	if err := ctr.uSvc.VerifyResetPassword(verifyReq); err != nil {
+	if err := ctr.uSvc.ResetPassword(req); err != nil {
+		if policyErr, ok := err.(*errors.PasswordPolicyError); ok {
+			restErr := errors.NewPasswordPolicyError(policyErr.Violations)
+			return c.JSON(restErr.Status, restErr)
+		}
 		switch err {
 		case errors.ErrParseJwt,
 			errors.ErrInvalidPasswordResetToken:
-			restErr := errors.NewUnauthorizedError("failed to send reset_token email")
+			restErr := errors.NewUnauthorizedError("invalid or expired reset_token")
 			return c.JSON(restErr.Status, restErr)
 		default:
 			restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
@@ -204,10 +341,5 @@ func (ctr *users) ResetPassword(c echo.Context) error {
 		}
 	}
 
-	if err := ctr.uSvc.ResetPassword(req); err != nil {
-		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
-		return c.JSON(restErr.Status, restErr)
-	}
-
 	return c.JSON(http.StatusOK, "password reset successful")
 }
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/app/utils/consts"
+	"next-oms/infra/errors"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey carries the api key of machine to machine requests
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyResolver finds the user an api key acts for
+type APIKeyResolver interface {
+	Authenticate(apiKey string) (*serializers.LoggedInUser, error)
+}
+
+var apiKeys APIKeyResolver
+
+// SetAPIKeyResolver sets where APIKey looks the keys up, it must be set before the server starts
+func SetAPIKeyResolver(r APIKeyResolver) {
+	apiKeys = r
+}
+
+// APIKey authenticates the requests which carry an api key instead of a bearer jwt, the key's
+// user is stored in the context under contextKey so the jwt middleware lets the request through.
+// A key only reaches the routes of its scopes
+func APIKey(contextKey string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey := c.Request().Header.Get(HeaderAPIKey)
+			if apiKey == "" {
+				return next(c)
+			}
+
+			user, err := apiKeys.Authenticate(apiKey)
+			if err != nil {
+				restErr := errors.NewUnauthorizedError(errors.ErrInvalidAPIKey.Error())
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			if !scopesAllow(user.Scopes, c.Request().Method, c.Path()) {
+				restErr := errors.NewForbiddenError("api key is not allowed to access this route")
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			c.Set(contextKey, user)
+
+			return next(c)
+		}
+	}
+}
+
+// scopesAllow reports whether one of the scopes covers the route, routes of no scope are denied
+func scopesAllow(scopes []string, method, path string) bool {
+	route := method + " " + strings.TrimPrefix(path, "/api")
+
+	for _, scope := range scopes {
+		for _, allowed := range consts.APIKeyScopeRoutes[scope] {
+			if allowed == route {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TokenUserResolver loads the roles & permissions of a user
+type TokenUserResolver interface {
+	GetTokenUser(userID uint) (*serializers.VerifyTokenResp, *errors.RestErr)
+}
+
+var tokenUsers TokenUserResolver
+
+// SetTokenUserResolver sets where RequirePermission looks the permissions of the caller up,
+// it must be set before the server starts
+func SetTokenUserResolver(r TokenUserResolver) {
+	tokenUsers = r
+}
+
+// RequirePermission lets the request through only if the logged-in user has every one of
+// the given permissions, super admins are allowed everything
+func RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("user").(*serializers.LoggedInUser)
+			if !ok {
+				restErr := errors.NewUnauthorizedError("no logged-in user found")
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			tokenUser, getErr := tokenUsers.GetTokenUser(uint(user.ID))
+			if getErr != nil {
+				return c.JSON(getErr.Status, getErr)
+			}
+
+			if !tokenUser.SuperAdmin && !hasPermissions(tokenUser.Permissions, permissions) {
+				restErr := errors.NewForbiddenError("you don't have permission to perform this action")
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func hasPermissions(granted, required []string) bool {
+	grantedSet := map[string]bool{}
+	for _, permission := range granted {
+		grantedSet[permission] = true
+	}
+
+	for _, permission := range required {
+		if !grantedSet[permission] {
+			return false
+		}
+	}
+
+	return true
+}
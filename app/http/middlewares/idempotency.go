@@ -0,0 +1,196 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"next-oms/app/serializers"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
+	"next-oms/infra/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyInProgress    = "in-progress"
+	idempotencyMaxKeyLength  = 255
+	idempotencyInProgressTtl = 60 // seconds, releases the key if the handler never finishes
+)
+
+// idempotencyRecord is the cached outcome of a request made with an Idempotency-Key
+type idempotencyRecord struct {
+	State    string `json:"state"`
+	BodyHash string `json:"body_hash"`
+	Status   int    `json:"status"`
+	Body     []byte `json:"body"`
+}
+
+// Idempotency replays the stored response when a client retries a request with the same
+// Idempotency-Key header, requests without the header are passed through untouched
+func Idempotency() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > idempotencyMaxKeyLength {
+				restErr := errors.NewBadRequestError(fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, idempotencyMaxKeyLength))
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			user, ok := c.Get("user").(*serializers.LoggedInUser)
+			if !ok {
+				restErr := errors.NewUnauthorizedError("no logged-in user found")
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				restErr := errors.NewBadRequestError("failed to read request body")
+				return c.JSON(restErr.Status, restErr)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			bodyHash := requestHash(c, body)
+
+			ctx := context.Background()
+			cacheKey := fmt.Sprintf("%s%d_%s", config.Cache().Redis.IdempotencyPrefix, user.ID, key)
+
+			acquired, err := cache.Client().SetNX(ctx, cacheKey, &idempotencyRecord{
+				State:    idempotencyInProgress,
+				BodyHash: bodyHash,
+			}, idempotencyInProgressTtl)
+			if err != nil {
+				restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+				return c.JSON(restErr.Status, restErr)
+			}
+
+			if !acquired {
+				return replayIdempotent(c, ctx, cacheKey, bodyHash)
+			}
+
+			recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				// server side failures are not final, let the client retry with the same key
+				_ = cache.Client().Del(ctx, cacheKey)
+				return nil
+			}
+
+			_ = cache.Client().Set(ctx, cacheKey, &idempotencyRecord{
+				BodyHash: bodyHash,
+				Status:   status,
+				Body:     recorder.body.Bytes(),
+			}, config.Cache().Redis.IdempotencyTtl)
+
+			return nil
+		}
+	}
+}
+
+// requestHash fingerprints the request a key was first used with. Multipart bodies are hashed by
+// their parts, the boundary is picked anew by the client on every retry
+func requestHash(c echo.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request().Method + " " + c.Path() + "\n"))
+
+	mediaType, params, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || mediaType != echo.MIMEMultipartForm || !hashMultipart(h, body, params["boundary"]) {
+		h.Write(body)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashMultipart writes the name, file name & content of every part, false on a malformed body so
+// the raw body is hashed instead & the handler reports the error
+func hashMultipart(h hash.Hash, body []byte, boundary string) bool {
+	if boundary == "" {
+		return false
+	}
+
+	parts := sha256.New()
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return false
+		}
+
+		fmt.Fprintf(parts, "%q %q %d\n", part.FormName(), part.FileName(), len(content))
+		parts.Write(content)
+	}
+
+	h.Write(parts.Sum(nil))
+	return true
+}
+
+// replayIdempotent answers a retried request from the stored record
+func replayIdempotent(c echo.Context, ctx context.Context, cacheKey, bodyHash string) error {
+	var record idempotencyRecord
+	if err := cache.Client().GetStruct(ctx, cacheKey, &record); err != nil {
+		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if record.BodyHash != bodyHash {
+		restErr := errors.NewUnprocessableEntityError(errors.ErrIdempotencyKeyReused)
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	if record.State == idempotencyInProgress {
+		restErr := errors.NewConflictError(errors.ErrIdempotencyKeyInUse)
+		return c.JSON(restErr.Status, restErr)
+	}
+
+	c.Response().Header().Set(IdempotentReplayedHeader, "true")
+	return c.JSONBlob(record.Status, record.Body)
+}
+
+// idempotencyRecorder copies the response body while it's written to the client
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyRecorder) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *idempotencyRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
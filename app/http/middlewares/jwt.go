@@ -56,6 +56,10 @@ type (
 		// Optional. Default value HS256.
 		SigningMethod string
 
+		// KeyFunc supplies the key to validate token with, it checks the signing method itself.
+		// Optional. Overrides SigningKey, SigningKeys & SigningMethod when set.
+		KeyFunc jwt.Keyfunc
+
 		// Context key to store user information from the token into context.
 		// Optional. Default value "user".
 		ContextKey string
@@ -139,7 +143,7 @@ func JWTWithConfig(config JWTConfig, lc *logger.LogClient) echo.MiddlewareFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultJWTConfig.Skipper
 	}
-	if config.SigningKey == nil && len(config.SigningKeys) == 0 {
+	if config.KeyFunc == nil && config.SigningKey == nil && len(config.SigningKeys) == 0 {
 		panic("echo: jwt middleware requires signing key")
 	}
 	if config.SigningMethod == "" {
@@ -173,6 +177,9 @@ func JWTWithConfig(config JWTConfig, lc *logger.LogClient) echo.MiddlewareFunc {
 
 		return config.SigningKey, nil
 	}
+	if config.KeyFunc != nil {
+		config.keyFunc = config.KeyFunc
+	}
 
 	// Initialize
 	parts := strings.Split(config.TokenLookup, ":")
@@ -263,6 +270,7 @@ func JWTWithConfig(config JWTConfig, lc *logger.LogClient) echo.MiddlewareFunc {
 				ID:          user.ID,
 				AccessUuid:  tokenDetails.AccessUuid,
 				RefreshUuid: tokenDetails.RefreshUuid,
+				FamilyID:    tokenDetails.FamilyID,
 			})
 
 			return next(c)
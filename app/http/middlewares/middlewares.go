@@ -2,9 +2,13 @@ package middlewares
 
 import (
 	"github.com/labstack/echo/v4"
+	"net"
 	"net/http"
 	"next-oms/infra/config"
+	"next-oms/infra/conn/storage"
+	"next-oms/infra/jwtkeys"
 	"next-oms/infra/logger"
+	"strings"
 
 	openMiddleware "github.com/go-openapi/runtime/middleware"
 	"github.com/labstack/echo-contrib/prometheus"
@@ -18,6 +22,13 @@ func Attach(e *echo.Echo, lc logger.LogClient) error {
 	// remove trailing slashes from each requests
 	e.Pre(middleware.RemoveTrailingSlash())
 
+	// the client ip is used by the login lock & the sessions, echo trusts any forwarded ip otherwise
+	extractor, err := ipExtractor(config.App().TrustedProxies)
+	if err != nil {
+		return err
+	}
+	e.IPExtractor = extractor
+
 	// echo middlewares, todo: add color to the log
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Format: EchoLogFormat}))
 	e.Use(middleware.Recover())
@@ -30,10 +41,22 @@ func Attach(e *echo.Echo, lc logger.LogClient) error {
 		Level: 5,
 	}))
 
+	// api keys of merchant systems, the jwt isn't needed once a key authenticated the request
+	e.Use(APIKey(config.Jwt().ContextKey))
+
 	e.Use(JWTWithConfig(JWTConfig{
 		Skipper: func(context echo.Context) bool {
+			if context.Get(config.Jwt().ContextKey) != nil {
+				return true
+			}
+
+			if path := storage.LocalPath(); path != "" && strings.HasPrefix(context.Request().URL.Path, path+"/") {
+				return true
+			}
+
 			switch context.Request().URL.Path {
 			case "/swagger.yaml",
+				"/.well-known/jwks.json",
 				"/docs/rapidoc",
 				"/docs/redoc",
 				"/docs/swagger",
@@ -41,24 +64,52 @@ func Attach(e *echo.Echo, lc logger.LogClient) error {
 				"/api/v1",
 				"/api/v1/h34l7h",
 				"/api/v1/login",
+				"/api/v1/login/2fa",
+				"/api/v1/login/2fa/enroll",
 				"/api/v1/token/verify",
 				"/api/v1/token/refresh",
 				"/api/v1/password/forgot",
 				"/api/v1/password/verifyreset",
 				"/api/v1/password/reset",
-				"/api/v1/users/signup":
+				"/api/v1/users/signup",
+				"/api/v1/users/verify",
+				"/api/v1/users/verify/resend":
 				return true
 			default:
 				return false
 			}
 		},
-		SigningKey: []byte(config.Jwt().AccessTokenSecret),
+		KeyFunc:    jwtkeys.Keyfunc,
 		ContextKey: config.Jwt().ContextKey,
 	}, &lc))
 
 	return nil
 }
 
+// ipExtractor takes the client ip from X-Forwarded-For when the request came through one of the
+// trusted proxies, otherwise from the connection
+func ipExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, cidr := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 // PrometheusMonitor will start a middleware which will be
 // exposed /metrics handler to be used by prometheus
 func PrometheusMonitor(e *echo.Echo) {
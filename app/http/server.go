@@ -4,8 +4,11 @@ import (
 	"context"
 	"github.com/labstack/echo/v4"
 	container "next-oms/app"
+	"next-oms/app/http/controllers"
 	"next-oms/app/http/middlewares"
 	"next-oms/infra/config"
+	"next-oms/infra/conn/mail"
+	"next-oms/infra/conn/storage"
 	"next-oms/infra/logger"
 	"os"
 	"os/signal"
@@ -28,6 +31,14 @@ func Start() {
 	dg.GET("/rapidoc", echo.WrapHandler(middlewares.RapiDocs()))
 	e.File("/swagger.yaml", "./swagger.yaml")
 
+	// uploaded files of the local storage, the s3 compatible storages serve them on their own
+	if path := storage.LocalPath(); path != "" {
+		e.Static(path, config.Storage().LocalDir)
+	}
+
+	// public keys of the access tokens for the other services
+	e.GET("/.well-known/jwks.json", controllers.JWKS)
+
 	// Create a new Prometheus server for metrics using Prometheus Middleware
 	echoProm := echo.New()
 
@@ -51,6 +62,9 @@ func Start() {
 
 	// graceful shutdown
 	GracefulShutdown(e, lc)
+
+	// deliver the mails still in the queue
+	mail.Client().Close()
 }
 
 // GracefulShutdown server will gracefully shut down within 5 sec
@@ -0,0 +1,46 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"time"
+)
+
+type apiKeys struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewAPIKeysRepository will create an object that represent the APIKeys.Repository implementations
+func NewAPIKeysRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.IAPIKeys {
+	return &apiKeys{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *apiKeys) SaveAPIKey(key *domain.APIKey) (*domain.APIKey, *errors.RestErr) {
+	return r.DB.SaveAPIKey(key)
+}
+
+func (r *apiKeys) GetAPIKeysByUser(userID uint) (domain.APIKeys, *errors.RestErr) {
+	return r.DB.GetAPIKeysByUser(userID)
+}
+
+func (r *apiKeys) GetAPIKeyByPrefix(prefix string) (*domain.APIKey, *errors.RestErr) {
+	return r.DB.GetAPIKeyByPrefix(prefix)
+}
+
+func (r *apiKeys) RevokeAPIKey(userID, id uint) *errors.RestErr {
+	return r.DB.RevokeAPIKey(userID, id)
+}
+
+func (r *apiKeys) SetAPIKeyUsed(id uint, usedAt time.Time) error {
+	return r.DB.SetAPIKeyUsed(id, usedAt)
+}
@@ -0,0 +1,66 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type companies struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewCompaniesRepository will create an object that represent the Companies.Repository implementations
+func NewCompaniesRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.ICompanies {
+	return &companies{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *companies) SaveBusiness(business *domain.Business) (*domain.Business, *errors.RestErr) {
+	return r.DB.SaveBusiness(business)
+}
+
+func (r *companies) GetBusinesses(filters *serializers.ListFilters) (domain.Businesses, *errors.RestErr) {
+	return r.DB.GetBusinesses(filters)
+}
+
+func (r *companies) GetBusinessByID(id uint) (*domain.Business, *errors.RestErr) {
+	return r.DB.GetBusinessByID(id)
+}
+
+func (r *companies) UpdateBusiness(business *domain.Business) *errors.RestErr {
+	return r.DB.UpdateBusiness(business)
+}
+
+func (r *companies) DeleteBusiness(id uint) *errors.RestErr {
+	return r.DB.DeleteBusiness(id)
+}
+
+func (r *companies) SaveCompany(company *domain.Company) (*domain.Company, *errors.RestErr) {
+	return r.DB.SaveCompany(company)
+}
+
+func (r *companies) GetCompanies(filters *serializers.ListFilters) (domain.Companies, *errors.RestErr) {
+	return r.DB.GetCompanies(filters)
+}
+
+func (r *companies) GetCompanyByID(id uint) (*domain.Company, *errors.RestErr) {
+	return r.DB.GetCompanyByID(id)
+}
+
+func (r *companies) UpdateCompany(company *domain.Company) *errors.RestErr {
+	return r.DB.UpdateCompany(company)
+}
+
+func (r *companies) DeleteCompany(id uint) *errors.RestErr {
+	return r.DB.DeleteCompany(id)
+}
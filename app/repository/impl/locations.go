@@ -0,0 +1,93 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type locations struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewLocationsRepository will create an object that represent the Locations.Repository implementations
+func NewLocationsRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.ILocations {
+	return &locations{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *locations) GetZonesByIDs(ids []uint) (domain.Zones, *errors.RestErr) {
+	return r.DB.GetZonesByIDs(ids)
+}
+
+func (r *locations) GetAreasByIDs(ids []uint) (domain.Areas, *errors.RestErr) {
+	return r.DB.GetAreasByIDs(ids)
+}
+
+func (r *locations) SaveCity(city *domain.City) (*domain.City, *errors.RestErr) {
+	return r.DB.SaveCity(city)
+}
+
+func (r *locations) GetCities() (domain.Cities, *errors.RestErr) {
+	return r.DB.GetCities()
+}
+
+func (r *locations) GetCityByID(id uint) (*domain.City, *errors.RestErr) {
+	return r.DB.GetCityByID(id)
+}
+
+func (r *locations) UpdateCity(city *domain.City) *errors.RestErr {
+	return r.DB.UpdateCity(city)
+}
+
+func (r *locations) DeleteCity(id uint) *errors.RestErr {
+	return r.DB.DeleteCity(id)
+}
+
+func (r *locations) SaveZone(zone *domain.Zone) (*domain.Zone, *errors.RestErr) {
+	return r.DB.SaveZone(zone)
+}
+
+func (r *locations) GetZones(cityID uint) (domain.Zones, *errors.RestErr) {
+	return r.DB.GetZones(cityID)
+}
+
+func (r *locations) GetZoneByID(id uint) (*domain.Zone, *errors.RestErr) {
+	return r.DB.GetZoneByID(id)
+}
+
+func (r *locations) UpdateZone(zone *domain.Zone) *errors.RestErr {
+	return r.DB.UpdateZone(zone)
+}
+
+func (r *locations) DeleteZone(id uint) *errors.RestErr {
+	return r.DB.DeleteZone(id)
+}
+
+func (r *locations) SaveArea(area *domain.Area) (*domain.Area, *errors.RestErr) {
+	return r.DB.SaveArea(area)
+}
+
+func (r *locations) GetAreas(zoneID uint) (domain.Areas, *errors.RestErr) {
+	return r.DB.GetAreas(zoneID)
+}
+
+func (r *locations) GetAreaByID(id uint) (*domain.Area, *errors.RestErr) {
+	return r.DB.GetAreaByID(id)
+}
+
+func (r *locations) UpdateArea(area *domain.Area) *errors.RestErr {
+	return r.DB.UpdateArea(area)
+}
+
+func (r *locations) DeleteArea(id uint) *errors.RestErr {
+	return r.DB.DeleteArea(id)
+}
@@ -25,14 +25,38 @@ func NewOrdersRepository(ctx context.Context, lc logger.LogClient, dbc db.Databa
 	}
 }
 
-func (r *orders) SaveOrder(user *domain.Order) (*domain.Order, *errors.RestErr) {
-	return r.DB.SaveOrder(user)
+func (r *orders) SaveOrder(order *domain.Order, actorID uint) (*domain.Order, *errors.RestErr) {
+	return r.DB.SaveOrder(order, actorID)
 }
 
-func (r *orders) GetOrders(filters *serializers.ListFilters) (domain.Orders, *errors.RestErr) {
-	return r.DB.GetOrders(filters)
+func (r *orders) SaveOrders(orders domain.Orders, actorID uint) *errors.RestErr {
+	return r.DB.SaveOrders(orders, actorID)
 }
 
-func (r *orders) CancelOrder(conID string) *errors.RestErr {
-	return r.DB.CancelOrder(conID)
+func (r *orders) GetOrders(scope *domain.TenantScope, filters *serializers.ListFilters) (domain.Orders, *errors.RestErr) {
+	return r.DB.GetOrders(scope, filters)
+}
+
+func (r *orders) GetOrderByConsignmentID(scope *domain.TenantScope, conID string) (*domain.Order, *errors.RestErr) {
+	return r.DB.GetOrderByConsignmentID(scope, conID)
+}
+
+func (r *orders) GetOrderByMerchantOrderID(scope *domain.TenantScope, storeID int, merchantOrderID string) (*domain.Order, *errors.RestErr) {
+	return r.DB.GetOrderByMerchantOrderID(scope, storeID, merchantOrderID)
+}
+
+func (r *orders) GetMerchantOrders(storeIDs []int, merchantOrderIDs []string) (domain.Orders, *errors.RestErr) {
+	return r.DB.GetMerchantOrders(storeIDs, merchantOrderIDs)
+}
+
+func (r *orders) GetLastConsignmentID(prefix string) (string, *errors.RestErr) {
+	return r.DB.GetLastConsignmentID(prefix)
+}
+
+func (r *orders) UpdateOrderStatus(history *domain.OrderHistory) *errors.RestErr {
+	return r.DB.UpdateOrderStatus(history)
+}
+
+func (r *orders) GetOrderHistory(conID string) (domain.OrderHistories, *errors.RestErr) {
+	return r.DB.GetOrderHistory(conID)
 }
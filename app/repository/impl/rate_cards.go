@@ -0,0 +1,50 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type rateCards struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewRateCardsRepository will create an object that represent the RateCards.Repository implementations
+func NewRateCardsRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.IRateCards {
+	return &rateCards{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *rateCards) SaveRateCard(card *domain.RateCard) (*domain.RateCard, *errors.RestErr) {
+	return r.DB.SaveRateCard(card)
+}
+
+func (r *rateCards) GetRateCards(filters *serializers.ListFilters) (domain.RateCards, *errors.RestErr) {
+	return r.DB.GetRateCards(filters)
+}
+
+func (r *rateCards) GetRateCardByID(id uint) (*domain.RateCard, *errors.RestErr) {
+	return r.DB.GetRateCardByID(id)
+}
+
+func (r *rateCards) GetActiveRateCard() (*domain.RateCard, *errors.RestErr) {
+	return r.DB.GetActiveRateCard()
+}
+
+func (r *rateCards) UpdateRateCard(card *domain.RateCard) (*domain.RateCard, *errors.RestErr) {
+	return r.DB.UpdateRateCard(card)
+}
+
+func (r *rateCards) DeleteRateCard(id uint) *errors.RestErr {
+	return r.DB.DeleteRateCard(id)
+}
@@ -0,0 +1,73 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type roles struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewRolesRepository will create an object that represent the Roles.Repository implementations
+func NewRolesRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.IRoles {
+	return &roles{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *roles) SaveRole(role *domain.Role) (*domain.Role, *errors.RestErr) {
+	return r.DB.SaveRole(role)
+}
+
+func (r *roles) GetRoles() (domain.Roles, *errors.RestErr) {
+	return r.DB.GetRoles()
+}
+
+func (r *roles) GetRoleByID(id uint) (*domain.Role, *errors.RestErr) {
+	return r.DB.GetRoleByID(id)
+}
+
+func (r *roles) UpdateRole(role *domain.Role) *errors.RestErr {
+	return r.DB.UpdateRole(role)
+}
+
+func (r *roles) DeleteRole(id uint) *errors.RestErr {
+	return r.DB.DeleteRole(id)
+}
+
+func (r *roles) SetRolePermissions(roleID uint, permissionIDs []uint) *errors.RestErr {
+	return r.DB.SetRolePermissions(roleID, permissionIDs)
+}
+
+func (r *roles) SavePermission(permission *domain.Permission) (*domain.Permission, *errors.RestErr) {
+	return r.DB.SavePermission(permission)
+}
+
+func (r *roles) GetPermissions() (domain.Permissions, *errors.RestErr) {
+	return r.DB.GetPermissions()
+}
+
+func (r *roles) GetPermissionByID(id uint) (*domain.Permission, *errors.RestErr) {
+	return r.DB.GetPermissionByID(id)
+}
+
+func (r *roles) GetPermissionsByIDs(ids []uint) (domain.Permissions, *errors.RestErr) {
+	return r.DB.GetPermissionsByIDs(ids)
+}
+
+func (r *roles) UpdatePermission(permission *domain.Permission) *errors.RestErr {
+	return r.DB.UpdatePermission(permission)
+}
+
+func (r *roles) DeletePermission(id uint) *errors.RestErr {
+	return r.DB.DeletePermission(id)
+}
@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type shipments struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewShipmentsRepository will create an object that represent the Shipments.Repository implementations
+func NewShipmentsRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.IShipments {
+	return &shipments{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *shipments) GetShipmentByConsignmentID(conID string) (*domain.Shipment, *errors.RestErr) {
+	return r.DB.GetShipmentByConsignmentID(conID)
+}
@@ -0,0 +1,50 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/infra/conn/db"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type stores struct {
+	ctx context.Context
+	lc  logger.LogClient
+	DB  db.DatabaseClient
+}
+
+// NewStoresRepository will create an object that represent the Stores.Repository implementations
+func NewStoresRepository(ctx context.Context, lc logger.LogClient, dbc db.DatabaseClient) repository.IStores {
+	return &stores{
+		ctx: ctx,
+		lc:  lc,
+		DB:  dbc,
+	}
+}
+
+func (r *stores) SaveStore(store *domain.Store) (*domain.Store, *errors.RestErr) {
+	return r.DB.SaveStore(store)
+}
+
+func (r *stores) GetStores(scope *domain.TenantScope, filters *serializers.ListFilters) (domain.Stores, *errors.RestErr) {
+	return r.DB.GetStores(scope, filters)
+}
+
+func (r *stores) GetStoreByID(scope *domain.TenantScope, id uint) (*domain.Store, *errors.RestErr) {
+	return r.DB.GetStoreByID(scope, id)
+}
+
+func (r *stores) GetStoresByIDs(scope *domain.TenantScope, ids []uint) (domain.Stores, *errors.RestErr) {
+	return r.DB.GetStoresByIDs(scope, ids)
+}
+
+func (r *stores) UpdateStore(store *domain.Store) *errors.RestErr {
+	return r.DB.UpdateStore(store)
+}
+
+func (r *stores) DeleteStore(id uint) *errors.RestErr {
+	return r.DB.DeleteStore(id)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"next-oms/app/domain"
 	"next-oms/app/repository"
+	"next-oms/app/serializers"
 	"next-oms/infra/conn/db"
 	"next-oms/infra/errors"
 	"next-oms/infra/logger"
@@ -37,8 +38,12 @@ func (r *users) UpdateUser(user *domain.User) *errors.RestErr {
 	return r.DB.UpdateUser(user)
 }
 
-func (r *users) UpdatePassword(userID uint, companyID uint, updateValues map[string]interface{}) *errors.RestErr {
-	return r.DB.UpdatePassword(userID, companyID, updateValues)
+func (r *users) SetUserPassword(userID uint, hashedPass []byte, historySize int) *errors.RestErr {
+	return r.DB.SetUserPassword(userID, hashedPass, historySize)
+}
+
+func (r *users) GetPasswordHistory(userID uint, limit int) ([]string, *errors.RestErr) {
+	return r.DB.GetPasswordHistory(userID, limit)
 }
 
 func (r *users) GetUserByEmail(email string) (*domain.User, error) {
@@ -52,10 +57,70 @@ func (r *users) SetLastLoginAt(user *domain.User) error {
 	return r.DB.SetLastLoginAt(user)
 }
 
-func (r *users) ResetPassword(userID int, hashedPass []byte) error {
-	return r.DB.ResetPassword(userID, hashedPass)
-}
-
 func (r *users) GetTokenUser(id uint) (*domain.VerifyTokenResp, *errors.RestErr) {
 	return r.DB.GetTokenUser(id)
 }
+
+func (r *users) GetUsersByCompany(companyID uint, filters *serializers.ListFilters) (domain.Users, *errors.RestErr) {
+	return r.DB.GetUsersByCompany(companyID, filters)
+}
+
+func (r *users) GetUsers(filters *serializers.ListFilters, deleted bool) (domain.Users, *errors.RestErr) {
+	return r.DB.GetUsers(filters, deleted)
+}
+
+func (r *users) GetDeletedUser(userID uint) (*domain.User, *errors.RestErr) {
+	return r.DB.GetDeletedUser(userID)
+}
+
+func (r *users) DeleteUser(userID uint) *errors.RestErr {
+	return r.DB.DeleteUser(userID)
+}
+
+func (r *users) RestoreUser(userID uint) *errors.RestErr {
+	return r.DB.RestoreUser(userID)
+}
+
+func (r *users) SetUserRole(userID, roleID uint) *errors.RestErr {
+	return r.DB.SetUserRole(userID, roleID)
+}
+
+func (r *users) SetUserProfilePic(userID uint, profilePic *string) *errors.RestErr {
+	return r.DB.SetUserProfilePic(userID, profilePic)
+}
+
+func (r *users) SetUserCompany(userID uint, companyID *uint) *errors.RestErr {
+	return r.DB.SetUserCompany(userID, companyID)
+}
+
+func (r *users) SetUserActive(userID uint, active bool) *errors.RestErr {
+	return r.DB.SetUserActive(userID, active)
+}
+
+func (r *users) SetUserVerified(userID uint) *errors.RestErr {
+	return r.DB.SetUserVerified(userID)
+}
+
+func (r *users) SetUserEmail(userID uint, email string) *errors.RestErr {
+	return r.DB.SetUserEmail(userID, email)
+}
+
+func (r *users) SetUserTotpSecret(userID uint, secret string) *errors.RestErr {
+	return r.DB.SetUserTotpSecret(userID, secret)
+}
+
+func (r *users) EnableUserTwoFactor(userID uint, backupCodeHashes []string) *errors.RestErr {
+	return r.DB.EnableUserTwoFactor(userID, backupCodeHashes)
+}
+
+func (r *users) DisableUserTwoFactor(userID uint) *errors.RestErr {
+	return r.DB.DisableUserTwoFactor(userID)
+}
+
+func (r *users) SetUserBackupCodes(userID uint, backupCodeHashes []string) *errors.RestErr {
+	return r.DB.SetUserBackupCodes(userID, backupCodeHashes)
+}
+
+func (r *users) UseBackupCode(userID uint, backupCodeHash string) (bool, error) {
+	return r.DB.UseBackupCode(userID, backupCodeHash)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "next-oms/app/domain"
+
+type IRoles interface {
+	domain.IRoles
+}
@@ -0,0 +1,39 @@
+package serializers
+
+import (
+	"next-oms/app/utils/consts"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type APIKeyReq struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 never expires
+}
+
+func (a APIKeyReq) Validate() error {
+	scopes := make([]interface{}, 0, len(consts.APIKeyScopeRoutes))
+	for scope := range consts.APIKeyScopeRoutes {
+		scopes = append(scopes, scope)
+	}
+
+	return v.ValidateStruct(&a,
+		v.Field(&a.Name, v.Required, v.Length(1, 255)),
+		v.Field(&a.Scopes, v.Required, v.Each(v.Required, v.In(scopes...))),
+		v.Field(&a.ExpiresInDays, v.Min(0), v.Max(3650)),
+	)
+}
+
+// APIKeyResp is a newly created api key, the key is only ever shown in this response
+type APIKeyResp struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Key       string     `json:"key"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CompanyID uint       `json:"company_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
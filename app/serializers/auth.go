@@ -3,6 +3,7 @@ package serializers
 import (
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"time"
 )
 
 type LoginReq struct {
@@ -20,6 +21,73 @@ type LoginResp struct {
 	TokenType    string              `json:"token_type"`
 	ExpiresIn    int64               `json:"expires_in"`
 	User         *UserWithParamsResp `json:"user"`
+	BackupCodes  []string            `json:"backup_codes,omitempty"` // only when the login enrolled the user in two factor
+}
+
+// TwoFactorChallengeResp is the first step of a login of a user with two factor, the challenge
+// token & a code go to POST /v1/login/2fa. Users whose role requires two factor but who aren't
+// enrolled yet get an enrollment code by email, which gets them the secret for their authenticator
+// app through POST /v1/login/2fa/enroll
+type TwoFactorChallengeResp struct {
+	ChallengeToken     string `json:"challenge_token"`
+	ExpiresIn          int    `json:"expires_in"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+type TwoFactorLoginEnrollReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	EnrollmentCode string `json:"enrollment_code"`
+}
+
+func (t TwoFactorLoginEnrollReq) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.ChallengeToken, v.Required),
+		v.Field(&t.EnrollmentCode, v.Required),
+	)
+}
+
+type TwoFactorLoginReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // totp or backup code
+}
+
+func (t TwoFactorLoginReq) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.ChallengeToken, v.Required),
+		v.Field(&t.Code, v.Required),
+	)
+}
+
+type TwoFactorEnrollResp struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+func (t TwoFactorCodeReq) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Code, v.Required),
+	)
+}
+
+type TwoFactorDisableReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+func (t TwoFactorDisableReq) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Password, v.Required),
+		v.Field(&t.Code, v.Required),
+	)
+}
+
+// BackupCodesResp are shown once, only their hashes are stored
+type BackupCodesResp struct {
+	BackupCodes []string `json:"backup_codes"`
 }
 
 type JwtToken struct {
@@ -28,10 +96,39 @@ type JwtToken struct {
 	RefreshToken  string `json:"rft"`
 	AccessUuid    string `json:"aid"`
 	RefreshUuid   string `json:"rid"`
+	FamilyID      string `json:"fid"`
 	AccessExpiry  int64  `json:"axp"`
 	RefreshExpiry int64  `json:"rxp"`
 }
 
+// TokenFamily is the chain of tokens rotated from a single login, only the latest refresh_token
+// of the family can be used
+type TokenFamily struct {
+	ID          string `json:"id"`
+	UserID      uint   `json:"uid"`
+	AccessUuid  string `json:"aid"`
+	RefreshUuid string `json:"rid"`
+	IP          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+	CreatedAt   int64  `json:"created_at"`
+	LastUsedAt  int64  `json:"last_used_at"`
+}
+
+// ClientInfo tells where a request came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type SessionResp struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
 type ChangePasswordReq struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
@@ -40,7 +137,7 @@ type ChangePasswordReq struct {
 func (c ChangePasswordReq) Validate() error {
 	return v.ValidateStruct(&c,
 		v.Field(&c.OldPassword, v.Required),
-		v.Field(&c.NewPassword, v.Required, v.Length(7, 0)),
+		v.Field(&c.NewPassword, v.Required),
 	)
 }
 
@@ -54,6 +151,26 @@ func (f ForgotPasswordReq) Validate() error {
 	)
 }
 
+type VerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
+func (ve VerifyEmailReq) Validate() error {
+	return v.ValidateStruct(&ve,
+		v.Field(&ve.Token, v.Required),
+	)
+}
+
+type ResendVerificationReq struct {
+	Email string `json:"email"`
+}
+
+func (rv ResendVerificationReq) Validate() error {
+	return v.ValidateStruct(&rv,
+		v.Field(&rv.Email, v.Required, is.EmailFormat),
+	)
+}
+
 type VerifyResetPasswordReq struct {
 	Token string `json:"token"`
 	ID    int    `json:"id"`
@@ -76,6 +193,6 @@ func (rp ResetPasswordReq) Validate() error {
 	return v.ValidateStruct(&rp,
 		v.Field(&rp.Token, v.Required),
 		v.Field(&rp.ID, v.Required),
-		v.Field(&rp.Password, v.Required, v.Length(8, 0)),
+		v.Field(&rp.Password, v.Required),
 	)
 }
@@ -0,0 +1,46 @@
+package serializers
+
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type BusinessReq struct {
+	Name string `json:"name"`
+}
+
+func (b BusinessReq) Validate() error {
+	return v.ValidateStruct(&b,
+		v.Field(&b.Name, v.Required, v.Length(1, 255)),
+	)
+}
+
+type CompanyReq struct {
+	Name       string `json:"name"`
+	BusinessID *uint  `json:"business_id"`
+}
+
+func (c CompanyReq) Validate() error {
+	return v.ValidateStruct(&c,
+		v.Field(&c.Name, v.Required, v.Length(1, 255)),
+		v.Field(&c.BusinessID, v.NilOrNotEmpty),
+	)
+}
+
+// InviteUserReq is a user a company admin adds to the company, the user sets the password
+// through the password reset link
+type InviteUserReq struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`
+}
+
+func (i InviteUserReq) Validate() error {
+	return v.ValidateStruct(&i,
+		v.Field(&i.Email, v.Required, is.EmailFormat),
+		v.Field(&i.FirstName, v.Required),
+		v.Field(&i.LastName, v.Required),
+		v.Field(&i.Phone, v.When(i.Phone != "", v.By(validatePhoneNumber))),
+	)
+}
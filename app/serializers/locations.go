@@ -0,0 +1,39 @@
+package serializers
+
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type CityReq struct {
+	Name string `json:"name"`
+}
+
+func (c CityReq) Validate() error {
+	return v.ValidateStruct(&c,
+		v.Field(&c.Name, v.Required),
+	)
+}
+
+type ZoneReq struct {
+	CityID uint   `json:"city_id"`
+	Name   string `json:"name"`
+}
+
+func (z ZoneReq) Validate() error {
+	return v.ValidateStruct(&z,
+		v.Field(&z.CityID, v.Required),
+		v.Field(&z.Name, v.Required),
+	)
+}
+
+type AreaReq struct {
+	ZoneID uint   `json:"zone_id"`
+	Name   string `json:"name"`
+}
+
+func (a AreaReq) Validate() error {
+	return v.ValidateStruct(&a,
+		v.Field(&a.ZoneID, v.Required),
+		v.Field(&a.Name, v.Required),
+	)
+}
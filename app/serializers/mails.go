@@ -0,0 +1,29 @@
+package serializers
+
+type ForgetPasswordMailReq struct {
+	To        string
+	FirstName string
+	UserID    uint
+	Token     string
+}
+
+type InvitationMailReq struct {
+	To          string
+	FirstName   string
+	CompanyName string
+	UserID      uint
+	Token       string
+}
+
+type VerificationMailReq struct {
+	To        string
+	FirstName string
+	Token     string
+}
+
+type TwoFactorEnrollmentMailReq struct {
+	To        string
+	FirstName string
+	Code      string
+	ExpiresIn int // seconds
+}
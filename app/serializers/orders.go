@@ -1,9 +1,17 @@
 package serializers
 
 import (
+	"encoding/csv"
+	goerrors "errors"
+	"fmt"
 	v "github.com/go-ozzo/ozzo-validation/v4"
+	"io"
+	"next-oms/app/utils/consts"
 	"next-oms/infra/errors"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 type OrderReq struct {
@@ -20,6 +28,9 @@ type OrderReq struct {
 	SpecialInstruction string  `json:"special_instruction"`
 	ItemQuantity       int     `json:"item_quantity"`
 	ItemWeight         float64 `json:"item_weight"`
+	ItemLength         float64 `json:"item_length"`
+	ItemWidth          float64 `json:"item_width"`
+	ItemHeight         float64 `json:"item_height"`
 	AmountToCollect    float64 `json:"amount_to_collect"`
 	ItemDescription    string  `json:"item_description"`
 }
@@ -29,28 +40,47 @@ type OrderResp struct {
 	MerchantOrderID string  `json:"merchant_order_id"`
 	OrderStatus     string  `json:"order_status"`
 	DeliveryFee     float64 `json:"delivery_fee"`
+	TotalFee        float64 `json:"total_fee"`
 }
 
 func (o OrderReq) Validate() error {
 	return v.ValidateStruct(&o,
-		// Fields with hardcoded values
-		v.Field(&o.StoreID, v.Required, v.In(131172)),  // Must be 131172
-		v.Field(&o.RecipientCity, v.Required, v.In(1)), // Must be 1
-		v.Field(&o.RecipientZone, v.Required, v.In(1)), // Must be 1
-		v.Field(&o.RecipientArea, v.Required, v.In(1)), // Must be 1
-		v.Field(&o.DeliveryType, v.Required, v.In(48)), // Must be 48
-		v.Field(&o.ItemType, v.Required, v.In(2)),      // Must be 2
-		v.Field(&o.ItemQuantity, v.Required, v.In(1)),  // Must be 1
-		v.Field(&o.ItemWeight, v.Required, v.In(0.5)),  // Must be 0.5
+		// Master data references, checked against the store & locations of the caller later on
+		v.Field(&o.StoreID, v.Required),
+		v.Field(&o.RecipientCity, v.Required),
+		v.Field(&o.RecipientZone, v.Required),
+		v.Field(&o.RecipientArea, v.Required),
+		v.Field(&o.DeliveryType, v.Required), // priced by the active rate card
+		v.Field(&o.ItemType, v.Required, v.By(validateItemType)),
+		v.Field(&o.ItemQuantity, v.Required, v.Min(1)),
+		v.Field(&o.ItemWeight, v.Required, v.Min(0.0).Exclusive()),
 
 		// Fields with user input (required)
 		v.Field(&o.RecipientName, v.Required),                             // Required
 		v.Field(&o.RecipientPhone, v.Required, v.By(validatePhoneNumber)), // Required and custom phone validation
 		v.Field(&o.RecipientAddress, v.Required),                          // Required
 		v.Field(&o.AmountToCollect, v.Required),                           // Required
+
+		// Optional parcel dimensions
+		v.Field(&o.ItemLength, v.Min(0.0)),
+		v.Field(&o.ItemWidth, v.Min(0.0)),
+		v.Field(&o.ItemHeight, v.Min(0.0)),
 	)
 }
 
+func validateItemType(value interface{}) error {
+	itemType, ok := value.(int)
+	if !ok {
+		return errors.NewError("invalid input type, expected an integer")
+	}
+
+	if _, exists := consts.ItemTypeMap[itemType]; !exists {
+		return errors.NewError("unknown item type")
+	}
+
+	return nil
+}
+
 func validatePhoneNumber(value interface{}) error {
 	// Type assertion to ensure the input is a string
 	phone, ok := value.(string)
@@ -68,3 +98,130 @@ func validatePhoneNumber(value interface{}) error {
 
 	return nil
 }
+
+type OrderStatusReq struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (o OrderStatusReq) Validate() error {
+	return v.ValidateStruct(&o,
+		v.Field(&o.Status, v.Required, v.In(
+			"Pending", "Picked", "In Transit", "Delivered", "Returned", "Cancelled",
+		)),
+	)
+}
+
+type CancelOrderReq struct {
+	Reason string `json:"reason"`
+}
+
+// BulkOrderRow is a single order of a bulk request, Err is set when the row couldn't be parsed
+type BulkOrderRow struct {
+	Order *OrderReq
+	Err   error
+}
+
+type BulkOrderResult struct {
+	Row           int    `json:"row"`
+	ConsignmentID string `json:"consignment_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type BulkOrderResp struct {
+	Total    int                `json:"total"`
+	Created  int                `json:"created"`
+	Rejected int                `json:"rejected"`
+	Results  []*BulkOrderResult `json:"results"`
+}
+
+// ParseOrdersCSV reads orders from a csv whose header holds the OrderReq json field names,
+// unknown columns are ignored and a malformed cell only rejects its own row. Reading stops at
+// the first row past BulkOrderMaxRows or when the file itself can't be read
+func ParseOrdersCSV(r io.Reader) ([]*BulkOrderRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.NewError("failed to read csv header")
+	}
+
+	orderType := reflect.TypeOf(OrderReq{})
+	columns := map[int]int{}
+
+	for i, name := range header {
+		for f := 0; f < orderType.NumField(); f++ {
+			if orderType.Field(f).Tag.Get("json") == strings.TrimSpace(name) {
+				columns[i] = f
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.NewError("csv header has no order column")
+	}
+
+	var rows []*BulkOrderRow
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !goerrors.As(err, &parseErr) {
+			return nil, errors.NewError("failed to read csv file")
+		}
+
+		if len(rows) == consts.BulkOrderMaxRows {
+			return nil, errors.NewError(fmt.Sprintf("at most %d orders can be created at once", consts.BulkOrderMaxRows))
+		}
+
+		row := &BulkOrderRow{Order: &OrderReq{}}
+		rows = append(rows, row)
+
+		if err != nil {
+			row.Err = errors.NewError("malformed csv row")
+			continue
+		}
+
+		value := reflect.ValueOf(row.Order).Elem()
+
+		for i, cell := range record {
+			f, ok := columns[i]
+			if !ok || cell == "" {
+				continue
+			}
+
+			if err := setOrderField(value.Field(f), cell); err != nil {
+				row.Err = errors.NewError(fmt.Sprintf("%s: %s", orderType.Field(f).Tag.Get("json"), err.Error()))
+				break
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func setOrderField(field reflect.Value, cell string) error {
+	switch field.Kind() {
+	case reflect.Int:
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return errors.NewError("must be an integer")
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return errors.NewError("must be a number")
+		}
+		field.SetFloat(n)
+	default:
+		field.SetString(cell)
+	}
+
+	return nil
+}
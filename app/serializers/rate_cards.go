@@ -0,0 +1,90 @@
+package serializers
+
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type RateCardReq struct {
+	Name            string              `json:"name"`
+	Active          bool                `json:"active"`
+	CodPercentage   float64             `json:"cod_percentage"`
+	DeliveryCharges []DeliveryChargeReq `json:"delivery_charges"`
+	ItemCharges     []ItemChargeReq     `json:"item_charges"`
+	ZoneCharges     []ZoneChargeReq     `json:"zone_charges"`
+	WeightBands     []WeightBandReq     `json:"weight_bands"`
+}
+
+func (r RateCardReq) Validate() error {
+	return v.ValidateStruct(&r,
+		v.Field(&r.Name, v.Required),
+		v.Field(&r.CodPercentage, v.Min(0.0), v.Max(100.0)),
+		v.Field(&r.DeliveryCharges, v.Required),
+		v.Field(&r.ItemCharges),
+		v.Field(&r.ZoneCharges),
+		v.Field(&r.WeightBands, v.Required),
+	)
+}
+
+type DeliveryChargeReq struct {
+	DeliveryType int     `json:"delivery_type"`
+	Fee          float64 `json:"fee"`
+}
+
+func (d DeliveryChargeReq) Validate() error {
+	return v.ValidateStruct(&d,
+		v.Field(&d.DeliveryType, v.Required),
+		v.Field(&d.Fee, v.Min(0.0)),
+	)
+}
+
+type ItemChargeReq struct {
+	ItemType int     `json:"item_type"`
+	Fee      float64 `json:"fee"`
+}
+
+func (i ItemChargeReq) Validate() error {
+	return v.ValidateStruct(&i,
+		v.Field(&i.ItemType, v.Required),
+		v.Field(&i.Fee, v.Min(0.0)),
+	)
+}
+
+type ZoneChargeReq struct {
+	ZoneID    int     `json:"zone_id"`
+	Surcharge float64 `json:"surcharge"`
+}
+
+func (z ZoneChargeReq) Validate() error {
+	return v.ValidateStruct(&z,
+		v.Field(&z.ZoneID, v.Required),
+		v.Field(&z.Surcharge, v.Min(0.0)),
+	)
+}
+
+type WeightBandReq struct {
+	MinWeight float64 `json:"min_weight"`
+	MaxWeight float64 `json:"max_weight"`
+	Fee       float64 `json:"fee"`
+}
+
+func (wb WeightBandReq) Validate() error {
+	return v.ValidateStruct(&wb,
+		v.Field(&wb.MinWeight, v.Min(0.0)),
+		v.Field(&wb.MaxWeight, v.Required, v.Min(wb.MinWeight).Exclusive()),
+		v.Field(&wb.Fee, v.Min(0.0)),
+	)
+}
+
+// FeeBreakdown is the price of an order split into its components
+type FeeBreakdown struct {
+	DeliveryFee     float64 `json:"delivery_fee"`
+	ItemFee         float64 `json:"item_fee"`
+	WeightFee       float64 `json:"weight_fee"`
+	CodFee          float64 `json:"cod_fee"`
+	ZoneSurcharge   float64 `json:"zone_surcharge"`
+	PromoDiscount   float64 `json:"promo_discount"`
+	Discount        float64 `json:"discount"`
+	TotalFee        float64 `json:"total_fee"`
+	RateCardID      uint    `json:"rate_card_id"`
+	RateCardVersion int     `json:"rate_card_version"`
+}
@@ -0,0 +1,45 @@
+package serializers
+
+import (
+	"regexp"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// permission names are dot separated lower case words, eg: order.status.update
+var permissionNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z0-9_]+)*$`)
+
+type RoleReq struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+}
+
+func (r RoleReq) Validate() error {
+	return v.ValidateStruct(&r,
+		v.Field(&r.Name, v.Required, v.Length(1, 64)),
+		v.Field(&r.Description, v.Length(0, 255)),
+	)
+}
+
+type PermissionReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (p PermissionReq) Validate() error {
+	return v.ValidateStruct(&p,
+		v.Field(&p.Name, v.Required, v.Length(1, 128), v.Match(permissionNameRegex)),
+		v.Field(&p.Description, v.Length(0, 255)),
+	)
+}
+
+type RolePermissionsReq struct {
+	PermissionIDs []uint `json:"permission_ids"`
+}
+
+func (r RolePermissionsReq) Validate() error {
+	return v.ValidateStruct(&r,
+		v.Field(&r.PermissionIDs, v.NotNil, v.Each(v.Required)),
+	)
+}
@@ -0,0 +1,25 @@
+package serializers
+
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type StoreReq struct {
+	Name         string `json:"name"`
+	ContactPhone string `json:"contact_phone"`
+	Address      string `json:"address"`
+	CityID       uint   `json:"city_id"`
+	ZoneID       uint   `json:"zone_id"`
+	AreaID       uint   `json:"area_id"`
+}
+
+func (s StoreReq) Validate() error {
+	return v.ValidateStruct(&s,
+		v.Field(&s.Name, v.Required),
+		v.Field(&s.ContactPhone, v.Required, v.By(validatePhoneNumber)),
+		v.Field(&s.Address, v.Required),
+		v.Field(&s.CityID, v.Required),
+		v.Field(&s.ZoneID, v.Required),
+		v.Field(&s.AreaID, v.Required),
+	)
+}
@@ -2,6 +2,9 @@ package serializers
 
 import (
 	"time"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
 type User struct {
@@ -26,20 +29,69 @@ type ResolveUserResponse struct {
 }
 
 type UserReq struct {
-	UserName   string  `json:"user_name,omitempty"`
-	FirstName  string  `json:"first_name,omitempty"`
-	LastName   string  `json:"last_name,omitempty"`
-	Email      string  `json:"email,omitempty"`
-	Password   *string `json:"password,omitempty"`
-	ProfilePic *string `json:"profile_pic,omitempty"`
-	Phone      string  `json:"phone,omitempty"`
-	CompanyID  uint    `json:"company_id"`
+	UserName  string  `json:"user_name,omitempty"`
+	FirstName string  `json:"first_name,omitempty"`
+	LastName  string  `json:"last_name,omitempty"`
+	Email     string  `json:"email,omitempty"`
+	Password  *string `json:"password,omitempty"`
+	Phone     string  `json:"phone,omitempty"`
+}
+
+func (u UserReq) Validate() error {
+	return v.ValidateStruct(&u,
+		v.Field(&u.Email, is.EmailFormat),
+	)
+}
+
+// AdminUserReq is a user an admin creates, unlike signup the role & company are set & the email
+// counts as verified
+type AdminUserReq struct {
+	UserName  string `json:"user_name"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Phone     string `json:"phone"`
+	CompanyID *uint  `json:"company_id"`
+	RoleID    uint   `json:"role_id"`
+}
+
+func (a AdminUserReq) Validate() error {
+	return v.ValidateStruct(&a,
+		v.Field(&a.UserName, v.Required),
+		v.Field(&a.FirstName, v.Required),
+		v.Field(&a.LastName, v.Required),
+		v.Field(&a.Email, v.Required, is.EmailFormat),
+		v.Field(&a.Password, v.Required),
+		v.Field(&a.Phone, v.When(a.Phone != "", v.By(validatePhoneNumber))),
+		v.Field(&a.CompanyID, v.NilOrNotEmpty),
+		v.Field(&a.RoleID, v.Required),
+	)
+}
+
+type UserRoleReq struct {
+	RoleID uint `json:"role_id"`
+}
+
+func (r UserRoleReq) Validate() error {
+	return v.ValidateStruct(&r,
+		v.Field(&r.RoleID, v.Required),
+	)
+}
+
+// AvatarResp is the uploaded avatar, the thumbnail URLs are keyed by their size in pixels
+type AvatarResp struct {
+	ProfilePic string         `json:"profile_pic"`
+	Thumbnails map[int]string `json:"thumbnails"`
 }
 
 type LoggedInUser struct {
-	ID          int    `json:"user_id"`
-	AccessUuid  string `json:"access_uuid"`
-	RefreshUuid string `json:"refresh_uuid"`
+	ID          int      `json:"user_id"`
+	AccessUuid  string   `json:"access_uuid"`
+	RefreshUuid string   `json:"refresh_uuid"`
+	FamilyID    string   `json:"family_id"`
+	APIKeyID    uint     `json:"api_key_id,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
 }
 
 type UserResp struct {
@@ -50,8 +102,14 @@ type UserResp struct {
 	Email       string     `json:"email"`
 	Phone       *string    `json:"phone"`
 	ProfilePic  *string    `json:"profile_pic"`
+	CompanyID   *uint      `json:"company_id"`
+	RoleID      uint       `json:"role_id"`
+	Active      bool       `json:"active"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	TwoFactorAt *time.Time `json:"two_factor_at"`
 	CreatedAt   time.Time  `json:"-"`
 	UpdatedAt   time.Time  `json:"-"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 	LastLoginAt *time.Time `json:"last_login_at"`
 	FirstLogin  bool       `json:"first_login"`
 }
@@ -89,6 +147,7 @@ type VerifyTokenResp struct {
 	CompanyName  string   `json:"company_name"`
 	Permissions  []string `json:"permissions"`
 	Admin        bool     `json:"admin"`
+	SuperAdmin   bool     `json:"super_admin"`
 }
 
 type UserWithLocations struct {
@@ -0,0 +1,14 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type IAPIKeys interface {
+	CreateAPIKey(userID uint, req *serializers.APIKeyReq) (*serializers.APIKeyResp, *errors.RestErr)
+	GetAPIKeys(userID uint) (domain.APIKeys, *errors.RestErr)
+	RevokeAPIKey(userID, id uint) *errors.RestErr
+	Authenticate(key string) (*serializers.LoggedInUser, error)
+}
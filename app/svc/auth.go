@@ -5,8 +5,13 @@ import (
 )
 
 type IAuth interface {
-	Login(req *serializers.LoginReq) (*serializers.LoginResp, error)
+	Login(req *serializers.LoginReq, client *serializers.ClientInfo) (*serializers.LoginResp, *serializers.TwoFactorChallengeResp, error)
+	LoginTwoFactor(req *serializers.TwoFactorLoginReq, client *serializers.ClientInfo) (*serializers.LoginResp, error)
+	EnrollTwoFactor(req *serializers.TwoFactorLoginEnrollReq, client *serializers.ClientInfo) (*serializers.TwoFactorEnrollResp, error)
 	Logout(user *serializers.LoggedInUser) error
-	RefreshToken(refreshToken string) (*serializers.LoginResp, error)
+	RefreshToken(refreshToken string, client *serializers.ClientInfo) (*serializers.LoginResp, error)
+	GetSessions(user *serializers.LoggedInUser) ([]*serializers.SessionResp, error)
+	RevokeSession(user *serializers.LoggedInUser, sessionID string) error
+	RevokeSessions(user *serializers.LoggedInUser) error
 	VerifyToken(accessToken string) (*serializers.VerifyTokenResp, error)
 }
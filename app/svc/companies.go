@@ -0,0 +1,20 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type ICompanies interface {
+	CreateBusiness(req *serializers.BusinessReq) (*domain.Business, *errors.RestErr)
+	GetBusinesses(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	GetBusiness(id uint) (*domain.Business, *errors.RestErr)
+	UpdateBusiness(id uint, req *serializers.BusinessReq) *errors.RestErr
+	DeleteBusiness(id uint) *errors.RestErr
+	CreateCompany(req *serializers.CompanyReq) (*domain.Company, *errors.RestErr)
+	GetCompanies(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	GetCompany(id uint) (*domain.Company, *errors.RestErr)
+	UpdateCompany(id uint, req *serializers.CompanyReq) *errors.RestErr
+	DeleteCompany(id uint) *errors.RestErr
+}
@@ -0,0 +1,180 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type apiKeys struct {
+	ctx   context.Context
+	lc    logger.LogClient
+	krepo repository.IAPIKeys
+	urepo repository.IUsers
+}
+
+func NewAPIKeysService(ctx context.Context, lc logger.LogClient, krepo repository.IAPIKeys, urepo repository.IUsers) svc.IAPIKeys {
+	return &apiKeys{
+		ctx:   ctx,
+		lc:    lc,
+		krepo: krepo,
+		urepo: urepo,
+	}
+}
+
+// CreateAPIKey creates a key for the user, the key is bound to the company of the user
+func (a *apiKeys) CreateAPIKey(userID uint, req *serializers.APIKeyReq) (*serializers.APIKeyResp, *errors.RestErr) {
+	user, getErr := a.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	if user.CompanyID == nil {
+		return nil, errors.NewBadRequestError("api keys can only be created by users of a company")
+	}
+
+	prefix, apiKey, err := newAPIKey()
+	if err != nil {
+		a.lc.Error("error occurred when generating api key", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	key := &domain.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(apiKey),
+		Scopes:    uniqueStrings(req.Scopes),
+		UserID:    user.ID,
+		CompanyID: *user.CompanyID,
+	}
+
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if _, saveErr := a.krepo.SaveAPIKey(key); saveErr != nil {
+		return nil, saveErr
+	}
+
+	a.lc.Info("api key " + prefix + " created by user " + strconv.Itoa(int(user.ID)))
+
+	return &serializers.APIKeyResp{
+		ID:        key.ID,
+		Name:      key.Name,
+		Key:       apiKey,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CompanyID: key.CompanyID,
+		ExpiresAt: key.ExpiresAt,
+		CreatedAt: key.CreatedAt,
+	}, nil
+}
+
+func (a *apiKeys) GetAPIKeys(userID uint) (domain.APIKeys, *errors.RestErr) {
+	return a.krepo.GetAPIKeysByUser(userID)
+}
+
+func (a *apiKeys) RevokeAPIKey(userID, id uint) *errors.RestErr {
+	if revokeErr := a.krepo.RevokeAPIKey(userID, id); revokeErr != nil {
+		return revokeErr
+	}
+
+	a.lc.Info("api key " + strconv.Itoa(int(id)) + " revoked by user " + strconv.Itoa(int(userID)))
+	return nil
+}
+
+// Authenticate finds the user an api key acts for, the key must be live and its user still an
+// active member of the company the key was created for
+func (a *apiKeys) Authenticate(apiKey string) (*serializers.LoggedInUser, error) {
+	// the secret part is base64url and may have underscores itself
+	parts := strings.SplitN(apiKey, "_", 3)
+	if len(parts) != 3 || parts[0] != consts.APIKeyPrefix || len(parts[1]) != consts.APIKeyPrefixLength {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	key, getErr := a.krepo.GetAPIKeyByPrefix(parts[1])
+	if getErr != nil {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(apiKey))) != 1 {
+		a.lc.Warn("api key " + key.Prefix + " used with a wrong secret")
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && key.ExpiresAt.Before(now)) {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	user, userErr := a.urepo.GetUserByID(key.UserID)
+	if userErr != nil {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	if !user.Active || user.CompanyID == nil || *user.CompanyID != key.CompanyID {
+		a.lc.Warn("api key " + key.Prefix + " used after its user left the company or was deactivated")
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > consts.APIKeyUsedThrottle*time.Second {
+		if err := a.krepo.SetAPIKeyUsed(key.ID, now); err != nil {
+			a.lc.Error("error occurred when updating last used of api key", err)
+		}
+	}
+
+	return &serializers.LoggedInUser{
+		ID:       int(user.ID),
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// newAPIKey generates a key as <APIKeyPrefix>_<prefix>_<secret>, the prefix is stored in
+// plain to find the key by
+func newAPIKey() (string, string, error) {
+	random := make([]byte, consts.APIKeyPrefixLength/2+consts.APIKeySecretLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+
+	prefix := hex.EncodeToString(random[:consts.APIKeyPrefixLength/2])
+	secret := base64.RawURLEncoding.EncodeToString(random[consts.APIKeyPrefixLength/2:])
+
+	return prefix, consts.APIKeyPrefix + "_" + prefix + "_" + secret, nil
+}
+
+// hashAPIKey hashes the whole key, the keys are random enough for a plain sha256
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	resp := []string{}
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			resp = append(resp, value)
+		}
+	}
+
+	return resp
+}
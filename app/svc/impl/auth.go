@@ -11,13 +11,18 @@ import (
 	"next-oms/app/utils/consts"
 	"next-oms/app/utils/methodsutil"
 	"next-oms/app/utils/msgutil"
+	"next-oms/app/utils/passwordutil"
 	"next-oms/infra/config"
 	"next-oms/infra/conn/cache"
 	"next-oms/infra/errors"
+	"next-oms/infra/jwtkeys"
 	"next-oms/infra/logger"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/go-redis/redis"
+	redis8 "github.com/go-redis/redis/v8"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -25,24 +30,35 @@ type auth struct {
 	ctx   context.Context
 	lc    logger.LogClient
 	urepo repository.IUsers
+	rrepo repository.IRoles
 	tSvc  svc.IToken
+	msvc  svc.IMails
 }
 
-func NewAuthService(ctx context.Context, lc logger.LogClient, urepo repository.IUsers, tokenSvc svc.IToken) svc.IAuth {
+func NewAuthService(ctx context.Context, lc logger.LogClient, urepo repository.IUsers, rrepo repository.IRoles, tokenSvc svc.IToken, msvc svc.IMails) svc.IAuth {
 	return &auth{
 		ctx:   ctx,
 		lc:    lc,
 		urepo: urepo,
+		rrepo: rrepo,
 		tSvc:  tokenSvc,
+		msvc:  msvc,
 	}
 }
 
-func (as *auth) Login(req *serializers.LoginReq) (*serializers.LoginResp, error) {
+// Login checks the credentials of the user, users with two factor get a challenge to finish the
+// login through LoginTwoFactor instead of the tokens
+func (as *auth) Login(req *serializers.LoginReq, client *serializers.ClientInfo) (*serializers.LoginResp, *serializers.TwoFactorChallengeResp, error) {
 	var user *domain.User
 	var err error
 
+	if as.loginLocked(req.Email, client.IP) {
+		return nil, nil, errors.ErrLoginLocked
+	}
+
 	if user, err = as.urepo.GetUserByEmail(req.Email); err != nil {
-		return nil, errors.ErrInvalidEmail
+		as.loginFailed(req.Email, client.IP)
+		return nil, nil, errors.ErrInvalidEmail
 	}
 
 	loginPass := []byte(req.Password)
@@ -50,17 +66,58 @@ func (as *auth) Login(req *serializers.LoginReq) (*serializers.LoginResp, error)
 
 	if err = bcrypt.CompareHashAndPassword(hashedPass, loginPass); err != nil {
 		as.lc.Error(err.Error(), err)
-		return nil, errors.ErrInvalidPassword
+		as.loginFailed(req.Email, client.IP)
+		return nil, nil, errors.ErrInvalidPassword
+	}
+
+	if !user.Active {
+		return nil, nil, errors.ErrUserInactive
+	}
+
+	if user.VerifiedAt == nil {
+		return nil, nil, errors.ErrEmailNotVerified
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+
+	if passwordutil.Expired(changedAt) {
+		return nil, nil, errors.ErrPasswordExpired
+	}
+
+	challenge, err := as.twoFactorChallenge(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the failed attempts are kept until the second step passes too
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	as.loginSucceeded(req.Email)
+
+	res, err := as.startSession(user, client)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	return res, nil, nil
+}
+
+// startSession issues the tokens of a new session of the user
+func (as *auth) startSession(user *domain.User, client *serializers.ClientInfo) (*serializers.LoginResp, error) {
 	var token *serializers.JwtToken
+	var err error
 
-	if token, err = as.tSvc.CreateToken(user.ID); err != nil {
+	if token, err = as.tSvc.CreateToken(user.ID, ""); err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrCreateJwt
 	}
 
-	if err = as.tSvc.StoreTokenUuid(user.ID, token); err != nil {
+	if err = as.tSvc.StoreTokenUuid(user.ID, token, client); err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrStoreTokenUuid
 	}
@@ -87,38 +144,77 @@ func (as *auth) Login(req *serializers.LoginReq) (*serializers.LoginResp, error)
 }
 
 func (as *auth) Logout(user *serializers.LoggedInUser) error {
+	if user.FamilyID != "" {
+		return as.tSvc.RevokeTokenFamily(user.FamilyID)
+	}
+
 	return as.tSvc.DeleteTokenUuid(
 		config.Cache().Redis.AccessUuidPrefix+user.AccessUuid,
 		config.Cache().Redis.RefreshUuidPrefix+user.RefreshUuid,
 	)
 }
 
-func (as *auth) RefreshToken(refreshToken string) (*serializers.LoginResp, error) {
+// RefreshToken rotates the tokens of the family the refresh_token belongs to. A refresh_token can be
+// used only once, using it again means it was leaked, so the whole family is revoked & the user
+// has to log in again
+func (as *auth) RefreshToken(refreshToken string, client *serializers.ClientInfo) (*serializers.LoginResp, error) {
 	oldToken, err := as.parseToken(refreshToken, consts.RefreshTokenType)
 	if err != nil {
 		return nil, errors.ErrInvalidRefreshToken
 	}
 
-	if !as.userBelongsToTokenUuid(int(oldToken.UserID), oldToken.RefreshUuid, consts.RefreshTokenType) {
+	// tokens issued before token families can't be rotated
+	if oldToken.FamilyID == "" {
 		return nil, errors.ErrInvalidRefreshToken
 	}
 
+	family, err := as.tSvc.GetTokenFamily(oldToken.FamilyID)
+	if err != nil || family.UserID != oldToken.UserID {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	consumed, err := cache.Client().Consume(as.ctx, config.Cache().Redis.RefreshUuidPrefix+oldToken.RefreshUuid)
+	if err != nil {
+		as.lc.Error(err.Error(), err)
+		return nil, errors.ErrDeleteOldTokenUuid
+	}
+
+	if !consumed {
+		as.lc.Warn(fmt.Sprintf("security: reuse of rotated refresh_token %s detected for user %d, revoking token family %s",
+			oldToken.RefreshUuid, oldToken.UserID, oldToken.FamilyID))
+
+		if err := as.tSvc.RevokeTokenFamily(oldToken.FamilyID); err != nil {
+			as.lc.Error("error occur when revoking token family "+oldToken.FamilyID, err)
+		}
+
+		return nil, errors.ErrRefreshTokenReused
+	}
+
+	user, getErr := as.urepo.GetUserByID(oldToken.UserID)
+	if getErr != nil {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	if !user.Active {
+		if err := as.tSvc.RevokeTokenFamily(oldToken.FamilyID); err != nil {
+			as.lc.Error("error occur when revoking token family "+oldToken.FamilyID, err)
+		}
+		return nil, errors.ErrUserInactive
+	}
+
 	var newToken *serializers.JwtToken
 
-	if newToken, err = as.tSvc.CreateToken(oldToken.UserID); err != nil {
+	if newToken, err = as.tSvc.CreateToken(oldToken.UserID, oldToken.FamilyID); err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrCreateJwt
 	}
 
-	if err = as.tSvc.DeleteTokenUuid(
-		config.Cache().Redis.AccessUuidPrefix+oldToken.AccessUuid,
-		config.Cache().Redis.RefreshUuidPrefix+oldToken.RefreshUuid,
-	); err != nil {
+	if err = as.tSvc.DeleteTokenUuid(config.Cache().Redis.AccessUuidPrefix + oldToken.AccessUuid); err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrDeleteOldTokenUuid
 	}
 
-	if err = as.tSvc.StoreTokenUuid(newToken.UserID, newToken); err != nil {
+	if err = as.tSvc.StoreTokenUuid(newToken.UserID, newToken, client); err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrStoreTokenUuid
 	}
@@ -138,6 +234,53 @@ func (as *auth) RefreshToken(refreshToken string) (*serializers.LoginResp, error
 	return res, nil
 }
 
+// GetSessions lists where the user is logged in, the session of the request is marked as current
+func (as *auth) GetSessions(user *serializers.LoggedInUser) ([]*serializers.SessionResp, error) {
+	families, err := as.tSvc.GetTokenFamilies(uint(user.ID))
+	if err != nil {
+		as.lc.Error("error occur when getting token families", err)
+		return nil, err
+	}
+
+	sessions := make([]*serializers.SessionResp, 0, len(families))
+	for _, family := range families {
+		sessions = append(sessions, &serializers.SessionResp{
+			ID:         family.ID,
+			IP:         family.IP,
+			UserAgent:  family.UserAgent,
+			CreatedAt:  time.Unix(family.CreatedAt, 0),
+			LastUsedAt: time.Unix(family.LastUsedAt, 0),
+			Current:    family.ID == user.FamilyID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession logs the user out of one of its sessions
+func (as *auth) RevokeSession(user *serializers.LoggedInUser, sessionID string) error {
+	family, err := as.tSvc.GetTokenFamily(sessionID)
+	if err == redis8.Nil || (err == nil && family.UserID != uint(user.ID)) {
+		return errors.ErrSessionNotFound
+	}
+
+	if err != nil {
+		as.lc.Error("error occur when getting token family", err)
+		return err
+	}
+
+	return as.tSvc.RevokeTokenFamily(sessionID)
+}
+
+// RevokeSessions logs the user out everywhere, the session of the request included
+func (as *auth) RevokeSessions(user *serializers.LoggedInUser) error {
+	return as.tSvc.RevokeTokenFamilies(uint(user.ID), "")
+}
+
 func (as *auth) VerifyToken(accessToken string) (*serializers.VerifyTokenResp, error) {
 	token, err := as.parseToken(accessToken, consts.AccessTokenType)
 	if err != nil {
@@ -213,13 +356,16 @@ func (as *auth) parseToken(token, tokenType string) (*serializers.JwtToken, erro
 }
 
 func (as *auth) parseTokenClaim(token, tokenType string) (jwt.MapClaims, error) {
-	secret := config.Jwt().AccessTokenSecret
+	var parsedToken *jwt.Token
+	var err error
 
+	// access tokens may be signed with a rotated key, refresh tokens are only read by us
 	if tokenType == consts.RefreshTokenType {
-		secret = config.Jwt().RefreshTokenSecret
+		parsedToken, err = methodsutil.ParseJwtToken(token, config.Jwt().RefreshTokenSecret)
+	} else {
+		parsedToken, err = jwt.Parse(token, jwtkeys.Keyfunc)
 	}
 
-	parsedToken, err := methodsutil.ParseJwtToken(token, secret)
 	if err != nil {
 		as.lc.Error(err.Error(), err)
 		return nil, errors.ErrParseJwt
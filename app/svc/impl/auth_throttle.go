@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
+	"next-oms/infra/metrics"
+	"strings"
+	"time"
+)
+
+const (
+	loginScopeEmail = "email"
+	loginScopeIp    = "ip"
+)
+
+type loginSubject struct {
+	scope string
+	value string
+	limit int
+}
+
+// loginSubjects are what failed logins are counted for, the email tried and the ip of the client
+func loginSubjects(email, ip string) []loginSubject {
+	conf := config.Login()
+
+	return []loginSubject{
+		{scope: loginScopeEmail, value: strings.ToLower(strings.TrimSpace(email)), limit: conf.MaxEmailAttempts},
+		{scope: loginScopeIp, value: ip, limit: conf.MaxIpAttempts},
+	}
+}
+
+func (s loginSubject) attemptKey() string {
+	return config.Cache().Redis.LoginAttemptPrefix + s.scope + "_" + s.value
+}
+
+func (s loginSubject) lockKey() string {
+	return config.Cache().Redis.LoginLockPrefix + s.scope + "_" + s.value
+}
+
+// loginLocked reports whether the email or the ip is locked out, redis errors let the login through
+func (as *auth) loginLocked(email, ip string) bool {
+	for _, subject := range loginSubjects(email, ip) {
+		if subject.value == "" {
+			continue
+		}
+
+		_, err := cache.Client().Get(as.ctx, subject.lockKey())
+		if err == nil {
+			metrics.LoginLockedAttempts.Inc()
+			return true
+		}
+
+		if err != redis.Nil {
+			as.lc.Error("error occur when checking login lock of "+subject.scope, err)
+		}
+	}
+
+	return false
+}
+
+// loginFailed counts the failed attempt for the email & the ip and locks out the ones which reached
+// their limit. Once an email has DelayAfter failed attempts every further failure is answered late,
+// starting with a second and doubling up to MaxDelay
+func (as *auth) loginFailed(email, ip string) {
+	conf := config.Login()
+	metrics.LoginFailures.Inc()
+
+	var emailAttempts int64
+
+	for _, subject := range loginSubjects(email, ip) {
+		if subject.value == "" {
+			continue
+		}
+
+		attempts, err := cache.Client().Incr(as.ctx, subject.attemptKey(), conf.AttemptWindow)
+		if err != nil {
+			as.lc.Error("error occur when counting failed login of "+subject.scope, err)
+			continue
+		}
+
+		if subject.scope == loginScopeEmail {
+			emailAttempts = attempts
+		}
+
+		if attempts < int64(subject.limit) {
+			continue
+		}
+
+		if err := cache.Client().Set(as.ctx, subject.lockKey(), true, conf.LockDuration); err != nil {
+			as.lc.Error("error occur when locking login of "+subject.scope, err)
+			continue
+		}
+
+		// the lock starts a new round of attempts once it expires
+		if err := cache.Client().Del(as.ctx, subject.attemptKey()); err != nil {
+			as.lc.Error("error occur when resetting failed logins of "+subject.scope, err)
+		}
+
+		metrics.LoginLockouts.WithLabelValues(subject.scope).Inc()
+		as.lc.Warn(fmt.Sprintf("login locked for %s %s after %d failed attempts", subject.scope, subject.value, attempts))
+	}
+
+	if excess := emailAttempts - int64(conf.DelayAfter); excess > 0 {
+		time.Sleep(loginDelay(excess, conf.MaxDelay))
+	}
+}
+
+// loginSucceeded forgets the failed attempts of the email, the attempts of the ip are kept as other
+// emails might be tried from the same ip
+func (as *auth) loginSucceeded(email string) {
+	subject := loginSubjects(email, "")[0]
+
+	if err := cache.Client().Del(as.ctx, subject.attemptKey()); err != nil {
+		as.lc.Error("error occur when resetting failed logins of "+subject.scope, err)
+	}
+}
+
+func loginDelay(excess int64, maxDelay int) time.Duration {
+	delay := time.Duration(maxDelay) * time.Second
+
+	if excess <= 6 {
+		if d := time.Duration(1<<(excess-1)) * time.Second; d < delay {
+			delay = d
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,208 @@
+package impl
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
+	"next-oms/infra/errors"
+	"strconv"
+)
+
+// twoFactorChallenge starts the second step of the login of users with two factor, users whose
+// role requires two factor but who aren't enrolled yet are mailed an enrollment code, the password
+// alone doesn't get them the secret. Nil means the user can log in with the password alone
+func (as *auth) twoFactorChallenge(user *domain.User) (*serializers.TwoFactorChallengeResp, error) {
+	resp := &serializers.TwoFactorChallengeResp{
+		ExpiresIn: config.TwoFactor().ChallengeExpiry,
+	}
+
+	if user.TwoFactorAt == nil {
+		role, getErr := as.rrepo.GetRoleByID(user.RoleID)
+		if getErr != nil {
+			return nil, errors.NewError(getErr.Message)
+		}
+
+		if !role.RequireTwoFactor {
+			return nil, nil
+		}
+
+		resp.EnrollmentRequired = true
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		as.lc.Error("error occurred when generating two factor challenge", err)
+		return nil, err
+	}
+
+	resp.ChallengeToken = hex.EncodeToString(random)
+
+	key := config.Cache().Redis.TwoFactorPrefix + resp.ChallengeToken
+	if err := cache.Client().Set(as.ctx, key, user.ID, resp.ExpiresIn); err != nil {
+		as.lc.Error("error occurred when storing two factor challenge", err)
+		return nil, err
+	}
+
+	if resp.EnrollmentRequired {
+		if err := as.sendEnrollmentCode(user, key, resp.ExpiresIn); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// sendEnrollmentCode mails the user a single use code for the enrollment of the challenge, only
+// its hash is kept
+func (as *auth) sendEnrollmentCode(user *domain.User, challengeKey string, expiresIn int) error {
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		as.lc.Error("error occurred when generating two factor enrollment code", err)
+		return err
+	}
+
+	if err := cache.Client().Set(as.ctx, challengeKey+"_enrollment", hashes[0], expiresIn); err != nil {
+		as.lc.Error("error occurred when storing two factor enrollment code", err)
+		return err
+	}
+
+	return as.msvc.SendTwoFactorEnrollmentEmail(serializers.TwoFactorEnrollmentMailReq{
+		To:        user.Email,
+		FirstName: user.FirstName,
+		Code:      codes[0],
+		ExpiresIn: expiresIn,
+	})
+}
+
+// EnrollTwoFactor gives the secret for the authenticator app to a user who has to enroll on login,
+// once the mailed enrollment code is confirmed. Wrong codes count against the attempts of the
+// challenge & as failed logins, like wrong two factor codes
+func (as *auth) EnrollTwoFactor(req *serializers.TwoFactorLoginEnrollReq, client *serializers.ClientInfo) (*serializers.TwoFactorEnrollResp, error) {
+	user, key, err := as.challengeUser(req.ChallengeToken, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorAt != nil {
+		return nil, errors.ErrInvalidEnrollmentCode
+	}
+
+	enrollmentKey := key + "_enrollment"
+
+	codeHash, err := cache.Client().Get(as.ctx, enrollmentKey)
+	if err != nil {
+		return nil, errors.ErrInvalidEnrollmentCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashBackupCode(req.EnrollmentCode))) != 1 {
+		as.lc.Warn("wrong two factor enrollment code for user " + strconv.Itoa(int(user.ID)))
+		as.loginFailed(user.Email, client.IP)
+		return nil, errors.ErrInvalidEnrollmentCode
+	}
+
+	// the code is single use, a concurrent request with the same one loses
+	if consumed, err := cache.Client().Consume(as.ctx, enrollmentKey); err != nil || !consumed {
+		return nil, errors.ErrInvalidEnrollmentCode
+	}
+
+	secret, uri, err := startTwoFactorEnrollment(as.urepo, user)
+	if err != nil {
+		as.lc.Error("error occurred when starting two factor enrollment", err)
+		return nil, err
+	}
+
+	return &serializers.TwoFactorEnrollResp{
+		Secret:     secret,
+		OtpauthUri: uri,
+	}, nil
+}
+
+// challengeUser returns the user of a challenge & the cache key of the challenge, every call
+// takes one of the attempts of the challenge
+func (as *auth) challengeUser(challengeToken string, client *serializers.ClientInfo) (*domain.User, string, error) {
+	conf := config.TwoFactor()
+	key := config.Cache().Redis.TwoFactorPrefix + challengeToken
+	attemptsKey := key + "_attempts"
+
+	userID, err := cache.Client().GetInt(as.ctx, key)
+	if err != nil {
+		return nil, "", errors.ErrInvalidTwoFactorChallenge
+	}
+
+	user, getErr := as.urepo.GetUserByID(uint(userID))
+	if getErr != nil {
+		return nil, "", errors.ErrInvalidTwoFactorChallenge
+	}
+
+	if as.loginLocked(user.Email, client.IP) {
+		return nil, "", errors.ErrLoginLocked
+	}
+
+	attempts, err := cache.Client().Incr(as.ctx, attemptsKey, conf.ChallengeExpiry)
+	if err != nil || attempts > int64(conf.MaxAttempts) {
+		_ = cache.Client().Del(as.ctx, key, attemptsKey, key+"_enrollment")
+		return nil, "", errors.ErrInvalidTwoFactorChallenge
+	}
+
+	if !user.Active {
+		return nil, "", errors.ErrUserInactive
+	}
+
+	return user, key, nil
+}
+
+// LoginTwoFactor finishes a login with the challenge token & a totp or backup code. A challenge
+// takes MaxAttempts wrong codes before it's dropped, the user has to log in with the password again.
+// Wrong codes count as failed logins of the email & ip, so they are locked out like wrong passwords
+func (as *auth) LoginTwoFactor(req *serializers.TwoFactorLoginReq, client *serializers.ClientInfo) (*serializers.LoginResp, error) {
+	user, key, err := as.challengeUser(req.ChallengeToken, client)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptsKey := key + "_attempts"
+	enrolling := user.TwoFactorAt == nil
+
+	var valid bool
+	if enrolling {
+		valid = verifyTotp(as.ctx, user, req.Code)
+	} else if valid, err = verifyTwoFactorCode(as.ctx, as.urepo, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		as.lc.Warn("wrong two factor code for user " + strconv.Itoa(int(user.ID)))
+		as.loginFailed(user.Email, client.IP)
+		return nil, errors.ErrInvalidTwoFactorCode
+	}
+
+	// the challenge is single use, a concurrent request with the same one loses
+	if consumed, err := cache.Client().Consume(as.ctx, key); err != nil || !consumed {
+		return nil, errors.ErrInvalidTwoFactorChallenge
+	}
+
+	_ = cache.Client().Del(as.ctx, attemptsKey)
+	as.loginSucceeded(user.Email)
+
+	var backupCodes []string
+
+	if enrolling {
+		if backupCodes, err = enableTwoFactor(as.urepo, user.ID); err != nil {
+			as.lc.Error("error occurred when enabling two factor", err)
+			return nil, err
+		}
+	}
+
+	res, err := as.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	res.BackupCodes = backupCodes
+
+	return res, nil
+}
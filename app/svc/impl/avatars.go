@@ -0,0 +1,133 @@
+package impl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"next-oms/app/serializers"
+	"next-oms/app/utils/imageutil"
+	"next-oms/infra/config"
+	"next-oms/infra/errors"
+	"path"
+
+	"github.com/google/uuid"
+)
+
+// avatarContentTypes are the sniffed content types of the images an avatar can be made of
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// UploadAvatar resizes the image to the avatar thumbnails, stores them & sets the largest one as
+// the profile pic of the user. The thumbnails of the previous avatar are deleted
+func (u *users) UploadAvatar(userID uint, data []byte) (*serializers.AvatarResp, *errors.RestErr) {
+	conf := config.Avatar()
+
+	if int64(len(data)) > conf.MaxSize {
+		return nil, errors.NewPayloadTooLargeError(fmt.Sprintf("avatar can be at most %d bytes", conf.MaxSize))
+	}
+
+	// the content type of the request can't be trusted, it's sniffed from the data
+	if contentType := http.DetectContentType(data); !avatarContentTypes[contentType] {
+		return nil, errors.NewUnsupportedMediaTypeError("avatar must be a jpeg, png or gif image")
+	}
+
+	// the dimensions are checked before decoding, a small file can still decode to a huge image
+	imgConf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid avatar image")
+	}
+
+	if imgConf.Width > conf.MaxDimension || imgConf.Height > conf.MaxDimension {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("avatar can be at most %dx%d pixels", conf.MaxDimension, conf.MaxDimension))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid avatar image")
+	}
+
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	dir := path.Join(avatarUserDir(userID), uuid.New().String())
+	resp := &serializers.AvatarResp{Thumbnails: map[int]string{}}
+	var stored []string
+
+	for _, size := range conf.Sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, imageutil.Thumbnail(img, size), &jpeg.Options{Quality: conf.Quality}); err != nil {
+			u.lc.Error("error occurred when encoding avatar thumbnail", err)
+			u.deleteAvatarFiles(stored)
+			return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		}
+
+		key := avatarKey(dir, size)
+		if err := u.store.Put(u.ctx, key, "image/jpeg", buf.Bytes()); err != nil {
+			u.lc.Error("error occurred when storing avatar thumbnail "+key, err)
+			u.deleteAvatarFiles(stored)
+			return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		}
+
+		stored = append(stored, key)
+		resp.Thumbnails[size] = u.store.URL(key)
+	}
+
+	resp.ProfilePic = u.store.URL(stored[0])
+
+	if setErr := u.urepo.SetUserProfilePic(userID, &resp.ProfilePic); setErr != nil {
+		u.deleteAvatarFiles(stored)
+		return nil, setErr
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if user.ProfilePic != nil {
+		u.deleteAvatarFiles(u.previousAvatarKeys(userID, *user.ProfilePic))
+	}
+
+	return resp, nil
+}
+
+// previousAvatarKeys returns the keys of the thumbnails of an avatar by its profile pic, a profile
+// pic which wasn't uploaded as an avatar of the same user has none
+func (u *users) previousAvatarKeys(userID uint, profilePic string) []string {
+	key, ok := u.store.Key(profilePic)
+	if !ok || path.Dir(path.Dir(key)) != avatarUserDir(userID) {
+		return nil
+	}
+
+	var keys []string
+	for _, size := range config.Avatar().Sizes {
+		keys = append(keys, avatarKey(path.Dir(key), size))
+	}
+
+	return keys
+}
+
+// deleteAvatarFiles deletes the thumbnails on a best effort basis, a failure only leaves a file behind
+func (u *users) deleteAvatarFiles(keys []string) {
+	for _, key := range keys {
+		if err := u.store.Delete(u.ctx, key); err != nil {
+			u.lc.Error("error occurred when deleting avatar thumbnail "+key, err)
+		}
+	}
+}
+
+func avatarUserDir(userID uint) string {
+	return fmt.Sprintf("avatars/%d", userID)
+}
+
+func avatarKey(dir string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", dir, size)
+}
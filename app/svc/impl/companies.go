@@ -0,0 +1,128 @@
+package impl
+
+import (
+	"context"
+	"net/http"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type companies struct {
+	ctx   context.Context
+	lc    logger.LogClient
+	crepo repository.ICompanies
+}
+
+func NewCompaniesService(ctx context.Context, lc logger.LogClient, crepo repository.ICompanies) svc.ICompanies {
+	return &companies{
+		ctx:   ctx,
+		lc:    lc,
+		crepo: crepo,
+	}
+}
+
+func (c *companies) CreateBusiness(req *serializers.BusinessReq) (*domain.Business, *errors.RestErr) {
+	return c.crepo.SaveBusiness(&domain.Business{Name: req.Name})
+}
+
+func (c *companies) GetBusinesses(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	result, err := c.crepo.GetBusinesses(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.Results = result
+	return filters, nil
+}
+
+func (c *companies) GetBusiness(id uint) (*domain.Business, *errors.RestErr) {
+	return c.crepo.GetBusinessByID(id)
+}
+
+func (c *companies) UpdateBusiness(id uint, req *serializers.BusinessReq) *errors.RestErr {
+	if _, getErr := c.crepo.GetBusinessByID(id); getErr != nil {
+		return getErr
+	}
+
+	if updateErr := c.crepo.UpdateBusiness(&domain.Business{ID: id, Name: req.Name}); updateErr != nil {
+		return updateErr
+	}
+
+	// the business name is part of the cached token users
+	return dropTokenUsers(c.ctx, c.lc)
+}
+
+func (c *companies) DeleteBusiness(id uint) *errors.RestErr {
+	return c.crepo.DeleteBusiness(id)
+}
+
+func (c *companies) CreateCompany(req *serializers.CompanyReq) (*domain.Company, *errors.RestErr) {
+	if refErr := c.validateBusiness(req.BusinessID); refErr != nil {
+		return nil, refErr
+	}
+
+	return c.crepo.SaveCompany(&domain.Company{
+		Name:       req.Name,
+		BusinessID: req.BusinessID,
+	})
+}
+
+func (c *companies) GetCompanies(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	result, err := c.crepo.GetCompanies(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.Results = result
+	return filters, nil
+}
+
+func (c *companies) GetCompany(id uint) (*domain.Company, *errors.RestErr) {
+	return c.crepo.GetCompanyByID(id)
+}
+
+func (c *companies) UpdateCompany(id uint, req *serializers.CompanyReq) *errors.RestErr {
+	if _, getErr := c.crepo.GetCompanyByID(id); getErr != nil {
+		return getErr
+	}
+
+	if refErr := c.validateBusiness(req.BusinessID); refErr != nil {
+		return refErr
+	}
+
+	updateErr := c.crepo.UpdateCompany(&domain.Company{
+		ID:         id,
+		Name:       req.Name,
+		BusinessID: req.BusinessID,
+	})
+	if updateErr != nil {
+		return updateErr
+	}
+
+	// the company & business are part of the cached token users
+	return dropTokenUsers(c.ctx, c.lc)
+}
+
+func (c *companies) DeleteCompany(id uint) *errors.RestErr {
+	return c.crepo.DeleteCompany(id)
+}
+
+// validateBusiness checks the business a company points to, companies may have none
+func (c *companies) validateBusiness(businessID *uint) *errors.RestErr {
+	if businessID == nil {
+		return nil
+	}
+
+	if _, getErr := c.crepo.GetBusinessByID(*businessID); getErr != nil {
+		if getErr.Status == http.StatusNotFound {
+			return errors.NewBadRequestError("business not found")
+		}
+		return getErr
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type locations struct {
+	ctx   context.Context
+	lc    logger.LogClient
+	lrepo repository.ILocations
+}
+
+func NewLocationsService(ctx context.Context, lc logger.LogClient, lrepo repository.ILocations) svc.ILocations {
+	return &locations{
+		ctx:   ctx,
+		lc:    lc,
+		lrepo: lrepo,
+	}
+}
+
+func (l *locations) CreateCity(req *serializers.CityReq) (*domain.City, *errors.RestErr) {
+	return l.lrepo.SaveCity(&domain.City{Name: req.Name})
+}
+
+func (l *locations) GetCities() (domain.Cities, *errors.RestErr) {
+	return l.lrepo.GetCities()
+}
+
+func (l *locations) GetCity(id uint) (*domain.City, *errors.RestErr) {
+	return l.lrepo.GetCityByID(id)
+}
+
+func (l *locations) UpdateCity(id uint, req *serializers.CityReq) *errors.RestErr {
+	if _, getErr := l.lrepo.GetCityByID(id); getErr != nil {
+		return getErr
+	}
+
+	return l.lrepo.UpdateCity(&domain.City{ID: id, Name: req.Name})
+}
+
+func (l *locations) DeleteCity(id uint) *errors.RestErr {
+	return l.lrepo.DeleteCity(id)
+}
+
+func (l *locations) CreateZone(req *serializers.ZoneReq) (*domain.Zone, *errors.RestErr) {
+	if _, getErr := l.lrepo.GetCityByID(req.CityID); getErr != nil {
+		return nil, getErr
+	}
+
+	return l.lrepo.SaveZone(&domain.Zone{CityID: req.CityID, Name: req.Name})
+}
+
+func (l *locations) GetZones(cityID uint) (domain.Zones, *errors.RestErr) {
+	return l.lrepo.GetZones(cityID)
+}
+
+func (l *locations) GetZone(id uint) (*domain.Zone, *errors.RestErr) {
+	return l.lrepo.GetZoneByID(id)
+}
+
+func (l *locations) UpdateZone(id uint, req *serializers.ZoneReq) *errors.RestErr {
+	if _, getErr := l.lrepo.GetZoneByID(id); getErr != nil {
+		return getErr
+	}
+
+	if _, getErr := l.lrepo.GetCityByID(req.CityID); getErr != nil {
+		return getErr
+	}
+
+	return l.lrepo.UpdateZone(&domain.Zone{ID: id, CityID: req.CityID, Name: req.Name})
+}
+
+func (l *locations) DeleteZone(id uint) *errors.RestErr {
+	return l.lrepo.DeleteZone(id)
+}
+
+func (l *locations) CreateArea(req *serializers.AreaReq) (*domain.Area, *errors.RestErr) {
+	if _, getErr := l.lrepo.GetZoneByID(req.ZoneID); getErr != nil {
+		return nil, getErr
+	}
+
+	return l.lrepo.SaveArea(&domain.Area{ZoneID: req.ZoneID, Name: req.Name})
+}
+
+func (l *locations) GetAreas(zoneID uint) (domain.Areas, *errors.RestErr) {
+	return l.lrepo.GetAreas(zoneID)
+}
+
+func (l *locations) GetArea(id uint) (*domain.Area, *errors.RestErr) {
+	return l.lrepo.GetAreaByID(id)
+}
+
+func (l *locations) UpdateArea(id uint, req *serializers.AreaReq) *errors.RestErr {
+	if _, getErr := l.lrepo.GetAreaByID(id); getErr != nil {
+		return getErr
+	}
+
+	if _, getErr := l.lrepo.GetZoneByID(req.ZoneID); getErr != nil {
+		return getErr
+	}
+
+	return l.lrepo.UpdateArea(&domain.Area{ID: id, ZoneID: req.ZoneID, Name: req.Name})
+}
+
+func (l *locations) DeleteArea(id uint) *errors.RestErr {
+	return l.lrepo.DeleteArea(id)
+}
+
+// ValidateLocation makes sure the zone belongs to the city and the area to the zone
+func (l *locations) GetZonesByIDs(ids []uint) (domain.Zones, *errors.RestErr) {
+	return l.lrepo.GetZonesByIDs(ids)
+}
+
+func (l *locations) GetAreasByIDs(ids []uint) (domain.Areas, *errors.RestErr) {
+	return l.lrepo.GetAreasByIDs(ids)
+}
+
+func (l *locations) ValidateLocation(cityID, zoneID, areaID uint) *errors.RestErr {
+	// a failed lookup leaves the zone or area nil, so it's reported as not found
+	zone, _ := l.lrepo.GetZoneByID(zoneID)
+	area, _ := l.lrepo.GetAreaByID(areaID)
+
+	return checkLocation(cityID, zoneID, zone, area)
+}
+
+// checkLocation checks the city, zone & area of a location are consistent, a nil zone or area
+// wasn't found
+func checkLocation(cityID, zoneID uint, zone *domain.Zone, area *domain.Area) *errors.RestErr {
+	if zone == nil {
+		return errors.NewBadRequestError("zone not found")
+	}
+
+	if zone.CityID != cityID {
+		return errors.NewBadRequestError("zone doesn't belong to the city")
+	}
+
+	if area == nil {
+		return errors.NewBadRequestError("area not found")
+	}
+
+	if area.ZoneID != zoneID {
+		return errors.NewBadRequestError("area doesn't belong to the zone")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"net/url"
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/templates"
+	"next-oms/infra/config"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"strconv"
+	texttemplate "text/template"
+)
+
+type mails struct {
+	ctx    context.Context
+	lc     logger.LogClient
+	mailer domain.IMailer
+	html   *htmltemplate.Template
+	text   *texttemplate.Template
+}
+
+func NewMailsService(ctx context.Context, lc logger.LogClient, mailer domain.IMailer) svc.IMails {
+	return &mails{
+		ctx:    ctx,
+		lc:     lc,
+		mailer: mailer,
+		html:   htmltemplate.Must(htmltemplate.ParseFS(templates.Mails, "mails/*.html")),
+		text:   texttemplate.Must(texttemplate.ParseFS(templates.Mails, "mails/*.txt")),
+	}
+}
+
+func (m *mails) SendForgotPasswordEmail(req serializers.ForgetPasswordMailReq) error {
+	return m.send(req.To, "Reset your password", "forgot_password", map[string]interface{}{
+		"AppName":   config.App().Name,
+		"FirstName": req.FirstName,
+		"Link":      resetPasswordLink(req.UserID, req.Token),
+	})
+}
+
+func (m *mails) SendInvitationEmail(req serializers.InvitationMailReq) error {
+	return m.send(req.To, "You have been invited to "+req.CompanyName, "invitation", map[string]interface{}{
+		"AppName":     config.App().Name,
+		"FirstName":   req.FirstName,
+		"CompanyName": req.CompanyName,
+		"Link":        resetPasswordLink(req.UserID, req.Token),
+	})
+}
+
+func (m *mails) SendVerificationEmail(req serializers.VerificationMailReq) error {
+	query := url.Values{}
+	query.Set("token", req.Token)
+
+	return m.send(req.To, "Verify your email address", "verify_email", map[string]interface{}{
+		"AppName":   config.App().Name,
+		"FirstName": req.FirstName,
+		"Link":      config.Mail().VerifyEmailUrl + "?" + query.Encode(),
+	})
+}
+
+func (m *mails) SendTwoFactorEnrollmentEmail(req serializers.TwoFactorEnrollmentMailReq) error {
+	return m.send(req.To, "Set up two factor authentication", "two_factor_enrollment", map[string]interface{}{
+		"AppName":   config.App().Name,
+		"FirstName": req.FirstName,
+		"Code":      req.Code,
+		"ExpiresIn": (req.ExpiresIn + 59) / 60,
+	})
+}
+
+// send renders the html & text bodies of the template and queues the mail
+func (m *mails) send(to, subject, tmpl string, data interface{}) error {
+	var htmlBody, textBody bytes.Buffer
+
+	if err := m.html.ExecuteTemplate(&htmlBody, tmpl+".html", data); err != nil {
+		m.lc.Error("error occurred when rendering html body of "+tmpl+" mail", err)
+		return errors.ErrSendingEmail
+	}
+
+	if err := m.text.ExecuteTemplate(&textBody, tmpl+".txt", data); err != nil {
+		m.lc.Error("error occurred when rendering text body of "+tmpl+" mail", err)
+		return errors.ErrSendingEmail
+	}
+
+	if err := m.mailer.Send(&domain.Mail{
+		To:       []string{to},
+		Subject:  subject,
+		TextBody: textBody.String(),
+		HTMLBody: htmlBody.String(),
+	}); err != nil {
+		return errors.ErrSendingEmail
+	}
+
+	return nil
+}
+
+func resetPasswordLink(userID uint, token string) string {
+	query := url.Values{}
+	query.Set("id", strconv.Itoa(int(userID)))
+	query.Set("token", token)
+
+	return config.Mail().ResetPasswordUrl + "?" + query.Encode()
+}
@@ -3,73 +3,404 @@ package impl
 import (
 	"context"
 	"fmt"
-	"math/rand/v2"
+	"github.com/go-redis/redis/v8"
+	"net/http"
 	"next-oms/app/domain"
 	"next-oms/app/repository"
 	"next-oms/app/serializers"
 	"next-oms/app/svc"
+	"next-oms/app/utils/conidutil"
 	"next-oms/app/utils/consts"
+	"next-oms/app/utils/methodsutil"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
 	"next-oms/infra/errors"
 	"next-oms/infra/logger"
+	"time"
 )
 
 type orders struct {
-	ctx   context.Context
-	lc    logger.LogClient
-	orepo repository.IOrders
+	ctx    context.Context
+	lc     logger.LogClient
+	orepo  repository.IOrders
+	srepo  repository.IShipments
+	pSvc   svc.IPricing
+	stSvc  svc.IStores
+	locSvc svc.ILocations
+	uSvc   svc.IUsers
 }
 
-func NewOrdersService(ctx context.Context, lc logger.LogClient, orepo repository.IOrders) svc.IOrders {
+func NewOrdersService(ctx context.Context, lc logger.LogClient, orepo repository.IOrders, srepo repository.IShipments,
+	pSvc svc.IPricing, stSvc svc.IStores, locSvc svc.ILocations, uSvc svc.IUsers) svc.IOrders {
 	return &orders{
-		ctx:   ctx,
-		lc:    lc,
-		orepo: orepo,
+		ctx:    ctx,
+		lc:     lc,
+		orepo:  orepo,
+		srepo:  srepo,
+		pSvc:   pSvc,
+		stSvc:  stSvc,
+		locSvc: locSvc,
+		uSvc:   uSvc,
 	}
 }
 
-func (o *orders) CreateOrder(order *serializers.OrderReq) (*serializers.OrderResp, *errors.RestErr) {
+// orderRefs is the master data the orders of a request point to, it's loaded once for all of
+// the orders instead of once per order
+type orderRefs struct {
+	scope          *domain.TenantScope
+	stores         map[int]bool
+	zones          map[uint]*domain.Zone
+	areas          map[uint]*domain.Area
+	merchantOrders map[string]bool
+	card           *domain.RateCard
+}
+
+// loadOrderRefs loads the stores of the scope, the locations, the stored merchant order ids and
+// the active rate card the orders point to
+func (o *orders) loadOrderRefs(scope *domain.TenantScope, reqs []*serializers.OrderReq) (*orderRefs, *errors.RestErr) {
+	refs := &orderRefs{
+		scope:          scope,
+		stores:         map[int]bool{},
+		zones:          map[uint]*domain.Zone{},
+		areas:          map[uint]*domain.Area{},
+		merchantOrders: map[string]bool{},
+	}
+
+	storeIDs := map[uint]bool{}
+	zoneIDs := map[uint]bool{}
+	areaIDs := map[uint]bool{}
+	merchantOrderIDs := map[string]bool{}
+
+	for _, req := range reqs {
+		storeIDs[uint(req.StoreID)] = true
+		zoneIDs[uint(req.RecipientZone)] = true
+		areaIDs[uint(req.RecipientArea)] = true
+
+		if req.MerchantOrderID != "" {
+			merchantOrderIDs[req.MerchantOrderID] = true
+		}
+	}
+
+	stores, getErr := o.stSvc.GetStoresByIDs(scope, idsOf(storeIDs))
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	var merchantStoreIDs []int
+	for _, store := range stores {
+		refs.stores[int(store.ID)] = true
+		merchantStoreIDs = append(merchantStoreIDs, int(store.ID))
+	}
+
+	zones, getErr := o.locSvc.GetZonesByIDs(idsOf(zoneIDs))
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	for _, zone := range zones {
+		refs.zones[zone.ID] = zone
+	}
+
+	areas, getErr := o.locSvc.GetAreasByIDs(idsOf(areaIDs))
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	for _, area := range areas {
+		refs.areas[area.ID] = area
+	}
+
+	if len(merchantOrderIDs) > 0 && len(merchantStoreIDs) > 0 {
+		var ids []string
+		for id := range merchantOrderIDs {
+			ids = append(ids, id)
+		}
+
+		stored, getErr := o.orepo.GetMerchantOrders(merchantStoreIDs, ids)
+		if getErr != nil {
+			return nil, getErr
+		}
+
+		for _, order := range stored {
+			refs.merchantOrders[merchantOrderKey(order.StoreID, order.MerchantOrderID)] = true
+		}
+	}
+
+	if refs.card, getErr = o.pSvc.GetActiveRateCard(); getErr != nil {
+		return nil, getErr
+	}
+
+	return refs, nil
+}
+
+// prepareOrder validates the master data references of an order, prices it and
+// builds the order to be stored
+func (o *orders) prepareOrder(refs *orderRefs, order *serializers.OrderReq) (*domain.Order, *errors.RestErr) {
+	if refErr := validateReferences(refs, order); refErr != nil {
+		return nil, refErr
+	}
+
+	if order.MerchantOrderID != "" && refs.merchantOrders[merchantOrderKey(order.StoreID, order.MerchantOrderID)] {
+		return nil, errors.NewConflictError("merchant_order_id already exists for the store")
+	}
+
+	fee, priceErr := o.pSvc.PriceOrderWith(refs.card, order)
+	if priceErr != nil {
+		return nil, priceErr
+	}
+
+	scope := refs.scope
+
 	orderType := 1
-	ord := domain.Order{
-		ConsignmentID:    fmt.Sprintf("CONS-%d-%s-%d", order.StoreID, order.RecipientName, rand.Int()),
+	conID, idErr := o.nextConsignmentID()
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	ord := &domain.Order{
+		ConsignmentID:    conID,
+		CompanyID:        scope.CompanyID,
+		CreatedBy:        scope.UserID,
+		StoreID:          order.StoreID,
 		Description:      order.ItemDescription,
 		MerchantOrderID:  order.MerchantOrderID,
 		RecipientName:    order.RecipientName,
 		RecipientAddress: order.RecipientAddress,
 		RecipientPhone:   order.RecipientPhone,
 		Amount:           order.AmountToCollect,
-		TotalFee:         consts.CalculateTotalFee(*order),
+		TotalFee:         fee.TotalFee,
 		Instruction:      order.SpecialInstruction,
 		OrderTypeID:      orderType,
-		CodFee:           0,
-		PromoDiscount:    0,
-		Discount:         0,
-		DeliveryFee:      consts.CalculateDeliveryFee(orderType, order.AmountToCollect),
+		CodFee:           fee.CodFee,
+		PromoDiscount:    fee.PromoDiscount,
+		Discount:         fee.Discount,
+		DeliveryFee:      fee.DeliveryFee,
+		ItemFee:          fee.ItemFee,
+		WeightFee:        fee.WeightFee,
+		ZoneSurcharge:    fee.ZoneSurcharge,
+		RateCardID:       fee.RateCardID,
+		RateCardVersion:  fee.RateCardVersion,
 		Status:           consts.OrderPending,
 		OrderType:        consts.GetOrderTypeDescription(orderType),
 		ItemType:         consts.GetItemTypeDescription(order.ItemType),
+		Shipment: &domain.Shipment{
+			ConsignmentID: conID,
+			StoreID:       order.StoreID,
+			RecipientCity: order.RecipientCity,
+			RecipientZone: order.RecipientZone,
+			RecipientArea: order.RecipientArea,
+			DeliveryType:  order.DeliveryType,
+			ItemType:      order.ItemType,
+			ItemQuantity:  order.ItemQuantity,
+			ItemWeight:    order.ItemWeight,
+			ItemLength:    order.ItemLength,
+			ItemWidth:     order.ItemWidth,
+			ItemHeight:    order.ItemHeight,
+		},
 	}
 
-	result, saveErr := o.orepo.SaveOrder(&ord)
-	if saveErr != nil {
-		return nil, saveErr
+	return ord, nil
+}
+
+func (o *orders) CreateOrder(userID uint, order *serializers.OrderReq) (*serializers.OrderResp, *errors.RestErr) {
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
 	}
 
-	//TODO: Create Shipment info : for now adding it to order
+	refs, loadErr := o.loadOrderRefs(scope, []*serializers.OrderReq{order})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	ord, prepErr := o.prepareOrder(refs, order)
+	if prepErr != nil {
+		return nil, prepErr
+	}
 
-	//TODO: Store order History
+	result, saveErr := o.orepo.SaveOrder(ord, userID)
+	if saveErr != nil {
+		return nil, saveErr
+	}
 
 	resp := &serializers.OrderResp{
 		ConsignmentID:   result.ConsignmentID,
 		MerchantOrderID: result.MerchantOrderID,
 		OrderStatus:     result.Status,
 		DeliveryFee:     result.DeliveryFee,
+		TotalFee:        result.TotalFee,
 	}
 
 	return resp, nil
 }
 
-func (o *orders) GetOrders(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
-	orders, err := o.orepo.GetOrders(filters)
+// CreateOrders validates every row on its own and stores the valid ones in batches,
+// the report tells which rows got created and why the others were rejected
+func (o *orders) CreateOrders(userID uint, rows []*serializers.BulkOrderRow) *serializers.BulkOrderResp {
+	resp := &serializers.BulkOrderResp{Total: len(rows)}
+
+	var valid []*serializers.OrderReq
+
+	for i, row := range rows {
+		result := &serializers.BulkOrderResult{Row: i + 1}
+		resp.Results = append(resp.Results, result)
+
+		if row.Err != nil {
+			result.Error = row.Err.Error()
+			continue
+		}
+
+		if err := row.Order.Validate(); err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		valid = append(valid, row.Order)
+	}
+
+	if len(valid) == 0 {
+		resp.Rejected = resp.Total
+		return resp
+	}
+
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return rejectRemainingRows(resp, scopeErr)
+	}
+
+	// the references of every valid row are loaded at once
+	refs, loadErr := o.loadOrderRefs(scope, valid)
+	if loadErr != nil {
+		return rejectRemainingRows(resp, loadErr)
+	}
+
+	var batch domain.Orders
+	var batchResults []*serializers.BulkOrderResult
+	// merchant order ids seen in this request, the db only knows the ones already stored
+	seen := map[string]bool{}
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		// a failed batch is rolled back as a whole, its rows are retried one by one so only the
+		// rows which fail on their own are rejected
+		saveErr := o.orepo.SaveOrders(batch, userID)
+		if saveErr != nil {
+			o.lc.Warn("bulk order batch failed, saving its orders one by one: " + saveErr.Message)
+		}
+
+		for i, result := range batchResults {
+			if saveErr != nil {
+				if _, err := o.orepo.SaveOrder(batch[i], userID); err != nil {
+					result.Error = err.Message
+					continue
+				}
+			}
+			result.ConsignmentID = batch[i].ConsignmentID
+			resp.Created++
+		}
+
+		batch, batchResults = nil, nil
+	}
+
+	for i, row := range rows {
+		result := resp.Results[i]
+		if result.Error != "" {
+			continue
+		}
+
+		seenKey := merchantOrderKey(row.Order.StoreID, row.Order.MerchantOrderID)
+		if row.Order.MerchantOrderID != "" && seen[seenKey] {
+			result.Error = "merchant_order_id is repeated in the request"
+			continue
+		}
+
+		ord, prepErr := o.prepareOrder(refs, row.Order)
+		if prepErr != nil {
+			result.Error = prepErr.Message
+			continue
+		}
+
+		seen[seenKey] = true
+
+		batch = append(batch, ord)
+		batchResults = append(batchResults, result)
+
+		if len(batch) == consts.BulkOrderBatchSize {
+			flush()
+		}
+	}
+
+	flush()
+
+	resp.Rejected = resp.Total - resp.Created
+	return resp
+}
+
+// rejectRemainingRows rejects the rows which weren't rejected yet with the error of the request
+func rejectRemainingRows(resp *serializers.BulkOrderResp, err *errors.RestErr) *serializers.BulkOrderResp {
+	for _, result := range resp.Results {
+		if result.Error == "" {
+			result.Error = err.Message
+		}
+	}
+
+	resp.Rejected = resp.Total
+	return resp
+}
+
+// QuoteOrder prices an order the same way CreateOrder does, without storing anything
+func (o *orders) QuoteOrder(userID uint, order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr) {
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	refs, loadErr := o.loadOrderRefs(scope, []*serializers.OrderReq{order})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	if refErr := validateReferences(refs, order); refErr != nil {
+		return nil, refErr
+	}
+
+	return o.pSvc.PriceOrderWith(refs.card, order)
+}
+
+// validateReferences checks the master data an order points to, the store must
+// belong to the tenant of the caller and the recipient location must be consistent
+func validateReferences(refs *orderRefs, order *serializers.OrderReq) *errors.RestErr {
+	if !refs.stores[order.StoreID] {
+		return errors.NewBadRequestError("store not found")
+	}
+
+	return checkLocation(uint(order.RecipientCity), uint(order.RecipientZone),
+		refs.zones[uint(order.RecipientZone)], refs.areas[uint(order.RecipientArea)])
+}
+
+func merchantOrderKey(storeID int, merchantOrderID string) string {
+	return fmt.Sprintf("%d:%s", storeID, merchantOrderID)
+}
+
+func idsOf(set map[uint]bool) []uint {
+	ids := make([]uint, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (o *orders) GetOrders(userID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	orders, err := o.orepo.GetOrders(scope, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +409,185 @@ func (o *orders) GetOrders(filters *serializers.ListFilters) (*serializers.ListF
 	return filters, nil
 }
 
-func (o *orders) CancelOrder(conID string) *errors.RestErr {
-	return o.orepo.CancelOrder(conID)
+func (o *orders) GetOrder(userID uint, conID string) (*domain.Order, *errors.RestErr) {
+	if err := conidutil.ValidateLookup(conID); err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	order, getErr := o.orepo.GetOrderByConsignmentID(scope, conID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	return o.withShipment(order)
+}
+
+func (o *orders) GetOrderByMerchantOrderID(userID uint, storeID int, merchantOrderID string) (*domain.Order, *errors.RestErr) {
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	order, getErr := o.orepo.GetOrderByMerchantOrderID(scope, storeID, merchantOrderID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	return o.withShipment(order)
+}
+
+func (o *orders) UpdateOrderStatus(conID string, userID uint, req *serializers.OrderStatusReq) *errors.RestErr {
+	return o.transitOrder(conID, req.Status, userID, req.Reason)
+}
+
+func (o *orders) CancelOrder(conID string, userID uint, req *serializers.CancelOrderReq) *errors.RestErr {
+	return o.transitOrder(conID, consts.OrderCancelled, userID, req.Reason)
+}
+
+func (o *orders) GetOrderHistory(userID uint, conID string) (domain.OrderHistories, *errors.RestErr) {
+	if err := conidutil.ValidateLookup(conID); err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	if _, getErr := o.orepo.GetOrderByConsignmentID(scope, conID); getErr != nil {
+		return nil, getErr
+	}
+
+	return o.orepo.GetOrderHistory(conID)
+}
+
+// tenantScope resolves the tenant the user acts for, admins and super admins aren't bound to one
+func tenantScope(uSvc svc.IUsers, userID uint) (*domain.TenantScope, *errors.RestErr) {
+	user, getErr := uSvc.GetTokenUser(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	scope := &domain.TenantScope{
+		UserID:     userID,
+		AllTenants: user.Admin || user.SuperAdmin,
+	}
+
+	if user.CompanyID != nil {
+		companyID := uint(*user.CompanyID)
+		scope.CompanyID = &companyID
+	}
+
+	return scope, nil
+}
+
+// nextConsignmentID takes the next sequence of the day from redis, the counter is seeded
+// from the last stored order of the day when redis doesn't have it, eg: after a flush
+func (o *orders) nextConsignmentID() (string, *errors.RestErr) {
+	now := time.Now()
+	prefix := conidutil.Prefix(now)
+	key := config.Cache().Redis.ConsignmentPrefix + prefix
+	ttl := int((48 * time.Hour).Seconds())
+
+	_, err := cache.Client().GetInt(o.ctx, key)
+	if err == redis.Nil {
+		lastID, getErr := o.orepo.GetLastConsignmentID(prefix)
+		if getErr != nil {
+			return "", getErr
+		}
+
+		// the first order of the day needs no seed, incr starts the counter at 1
+		if lastID != "" {
+			lastSeq, seqErr := conidutil.Sequence(lastID)
+			if seqErr != nil {
+				o.lc.Error(fmt.Sprintf("malformed consignment id %s in orders", lastID), seqErr)
+				return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			}
+
+			// losing the race to another instance is fine, it seeded the same value
+			if _, err = cache.Client().SetNX(o.ctx, key, lastSeq, ttl); err != nil {
+				o.lc.Error("error occurred when seeding consignment sequence", err)
+				return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+			}
+		}
+	} else if err != nil {
+		o.lc.Error("error occurred when reading consignment sequence", err)
+		return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	seq, err := cache.Client().Incr(o.ctx, key, ttl)
+	if err != nil {
+		o.lc.Error("error occurred when incrementing consignment sequence", err)
+		return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	conID, err := conidutil.Generate(now, uint64(seq))
+	if err != nil {
+		o.lc.Error("error occurred when generating consignment id", err)
+		return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return conID, nil
+}
+
+// withShipment attaches the shipment to the order, orders created before
+// shipments were stored separately have none
+func (o *orders) withShipment(order *domain.Order) (*domain.Order, *errors.RestErr) {
+	shipment, getErr := o.srepo.GetShipmentByConsignmentID(order.ConsignmentID)
+	if getErr != nil && getErr.Status != http.StatusNotFound {
+		return nil, getErr
+	}
+
+	order.Shipment = shipment
+	return order, nil
+}
+
+// transitOrder moves an order to the given status if the lifecycle allows it
+// and keeps a trace of the change in the order history
+func (o *orders) transitOrder(conID, toStatus string, userID uint, reason string) *errors.RestErr {
+	if err := conidutil.ValidateLookup(conID); err != nil {
+		return errors.NewBadRequestError(err.Error())
+	}
+
+	scope, scopeErr := tenantScope(o.uSvc, userID)
+	if scopeErr != nil {
+		return scopeErr
+	}
+
+	order, getErr := o.orepo.GetOrderByConsignmentID(scope, conID)
+	if getErr != nil {
+		return getErr
+	}
+
+	if !canTransit(order.Status, toStatus) {
+		o.lc.Error(fmt.Sprintf("order %s: %s -> %s", conID, order.Status, toStatus), errors.ErrInvalidStatusTransition)
+		return errors.NewConflictError(fmt.Sprintf("order can't be moved from %s to %s", order.Status, toStatus))
+	}
+
+	return o.orepo.UpdateOrderStatus(&domain.OrderHistory{
+		ConsignmentID: conID,
+		OldStatus:     order.Status,
+		NewStatus:     toStatus,
+		ActorID:       userID,
+		Reason:        reason,
+	})
+}
+
+// orderTransitions defines the order lifecycle, each status maps to the statuses it can move to
+var orderTransitions = map[string][]string{
+	consts.OrderPending:   {consts.OrderPicked, consts.OrderCancelled},
+	consts.OrderPicked:    {consts.OrderInTransit, consts.OrderCancelled},
+	consts.OrderInTransit: {consts.OrderDelivered, consts.OrderReturned, consts.OrderCancelled},
+	consts.OrderDelivered: {},
+	consts.OrderReturned:  {},
+	consts.OrderCancelled: {},
+}
+
+func canTransit(fromStatus, toStatus string) bool {
+	return methodsutil.Contains(toStatus, orderTransitions[fromStatus])
 }
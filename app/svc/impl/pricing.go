@@ -0,0 +1,171 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/methodsutil"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type pricing struct {
+	ctx    context.Context
+	lc     logger.LogClient
+	rcrepo repository.IRateCards
+}
+
+func NewPricingService(ctx context.Context, lc logger.LogClient, rcrepo repository.IRateCards) svc.IPricing {
+	return &pricing{
+		ctx:    ctx,
+		lc:     lc,
+		rcrepo: rcrepo,
+	}
+}
+
+func (p *pricing) CreateRateCard(req *serializers.RateCardReq) (*domain.RateCard, *errors.RestErr) {
+	card := &domain.RateCard{}
+
+	if err := methodsutil.StructToStruct(req, card); err != nil {
+		p.lc.Error(msgutil.EntityStructToStructFailedMsg("create rate card"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return p.rcrepo.SaveRateCard(card)
+}
+
+func (p *pricing) GetRateCards(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	cards, err := p.rcrepo.GetRateCards(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.Results = cards
+	return filters, nil
+}
+
+func (p *pricing) GetRateCard(id uint) (*domain.RateCard, *errors.RestErr) {
+	return p.rcrepo.GetRateCardByID(id)
+}
+
+func (p *pricing) UpdateRateCard(id uint, req *serializers.RateCardReq) (*domain.RateCard, *errors.RestErr) {
+	card := &domain.RateCard{}
+
+	if err := methodsutil.StructToStruct(req, card); err != nil {
+		p.lc.Error(msgutil.EntityStructToStructFailedMsg("update rate card"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	card.ID = id
+
+	return p.rcrepo.UpdateRateCard(card)
+}
+
+func (p *pricing) DeleteRateCard(id uint) *errors.RestErr {
+	return p.rcrepo.DeleteRateCard(id)
+}
+
+// GetActiveRateCard returns the rate card orders are priced with, along with its charges
+func (p *pricing) GetActiveRateCard() (*domain.RateCard, *errors.RestErr) {
+	card, getErr := p.rcrepo.GetActiveRateCard()
+	if getErr != nil {
+		if getErr.Status == http.StatusNotFound {
+			p.lc.Error("no active rate card to price the order", errors.ErrNoActiveRateCard)
+			return nil, errors.NewInternalServerError("pricing is not configured")
+		}
+		return nil, getErr
+	}
+
+	return card, nil
+}
+
+// PriceOrder calculates the fees of an order with the active rate card
+func (p *pricing) PriceOrder(order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr) {
+	card, getErr := p.GetActiveRateCard()
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	return p.PriceOrderWith(card, order)
+}
+
+// PriceOrderWith calculates the fees of an order with the given rate card, so many orders can be
+// priced with a single load of the card
+func (p *pricing) PriceOrderWith(card *domain.RateCard, order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr) {
+	deliveryFee, ok := deliveryFeeOf(card, order.DeliveryType)
+	if !ok {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("delivery type %d is not serviceable", order.DeliveryType))
+	}
+
+	weightFee, ok := weightFeeOf(card, order.ItemWeight)
+	if !ok {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("item weight %v is not serviceable", order.ItemWeight))
+	}
+
+	fee := &serializers.FeeBreakdown{
+		DeliveryFee:     deliveryFee,
+		ItemFee:         itemFeeOf(card, order.ItemType),
+		WeightFee:       weightFee,
+		CodFee:          roundFee(order.AmountToCollect * card.CodPercentage / 100),
+		ZoneSurcharge:   zoneSurchargeOf(card, order.RecipientZone),
+		RateCardID:      card.ID,
+		RateCardVersion: card.Version,
+	}
+
+	fee.TotalFee = roundFee(fee.DeliveryFee + fee.ItemFee + fee.WeightFee + fee.CodFee + fee.ZoneSurcharge -
+		fee.PromoDiscount - fee.Discount)
+
+	return fee, nil
+}
+
+func deliveryFeeOf(card *domain.RateCard, deliveryType int) (float64, bool) {
+	for _, charge := range card.DeliveryCharges {
+		if charge.DeliveryType == deliveryType {
+			return charge.Fee, true
+		}
+	}
+
+	return 0, false
+}
+
+func weightFeeOf(card *domain.RateCard, weight float64) (float64, bool) {
+	for _, band := range card.WeightBands {
+		if weight >= band.MinWeight && weight < band.MaxWeight {
+			return band.Fee, true
+		}
+	}
+
+	return 0, false
+}
+
+// itemFeeOf returns the item type fee, item types without a charge are free
+func itemFeeOf(card *domain.RateCard, itemType int) float64 {
+	for _, charge := range card.ItemCharges {
+		if charge.ItemType == itemType {
+			return charge.Fee
+		}
+	}
+
+	return 0
+}
+
+// zoneSurchargeOf returns the zone surcharge, only the listed zones have one
+func zoneSurchargeOf(card *domain.RateCard, zoneID int) float64 {
+	for _, charge := range card.ZoneCharges {
+		if charge.ZoneID == zoneID {
+			return charge.Surcharge
+		}
+	}
+
+	return 0
+}
+
+func roundFee(fee float64) float64 {
+	return math.Round(fee*100) / 100
+}
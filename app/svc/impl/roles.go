@@ -0,0 +1,184 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type roles struct {
+	ctx   context.Context
+	lc    logger.LogClient
+	rrepo repository.IRoles
+}
+
+func NewRolesService(ctx context.Context, lc logger.LogClient, rrepo repository.IRoles) svc.IRoles {
+	return &roles{
+		ctx:   ctx,
+		lc:    lc,
+		rrepo: rrepo,
+	}
+}
+
+func (r *roles) CreateRole(req *serializers.RoleReq) (*domain.Role, *errors.RestErr) {
+	return r.rrepo.SaveRole(&domain.Role{
+		Name:             req.Name,
+		Description:      req.Description,
+		RequireTwoFactor: req.RequireTwoFactor,
+	})
+}
+
+func (r *roles) GetRoles() (domain.Roles, *errors.RestErr) {
+	return r.rrepo.GetRoles()
+}
+
+func (r *roles) GetRole(id uint) (*domain.Role, *errors.RestErr) {
+	return r.rrepo.GetRoleByID(id)
+}
+
+func (r *roles) UpdateRole(id uint, req *serializers.RoleReq) *errors.RestErr {
+	if _, getErr := r.rrepo.GetRoleByID(id); getErr != nil {
+		return getErr
+	}
+
+	return r.rrepo.UpdateRole(&domain.Role{
+		ID:               id,
+		Name:             req.Name,
+		Description:      req.Description,
+		RequireTwoFactor: req.RequireTwoFactor,
+	})
+}
+
+// DeleteRole removes a role, the built-in ones can't be removed
+func (r *roles) DeleteRole(id uint) *errors.RestErr {
+	if _, builtIn := consts.RoleNames[id]; builtIn {
+		return errors.NewConflictError("built-in roles can't be deleted")
+	}
+
+	return r.rrepo.DeleteRole(id)
+}
+
+// SetRolePermissions replaces the permissions of a role, every permission must exist
+func (r *roles) SetRolePermissions(id uint, req *serializers.RolePermissionsReq) *errors.RestErr {
+	if _, getErr := r.rrepo.GetRoleByID(id); getErr != nil {
+		return getErr
+	}
+
+	permissionIDs := uniqueIDs(req.PermissionIDs)
+
+	if len(permissionIDs) > 0 {
+		permissions, getErr := r.rrepo.GetPermissionsByIDs(permissionIDs)
+		if getErr != nil {
+			return getErr
+		}
+
+		if len(permissions) != len(permissionIDs) {
+			return errors.NewBadRequestError("permission not found")
+		}
+	}
+
+	if setErr := r.rrepo.SetRolePermissions(id, permissionIDs); setErr != nil {
+		return setErr
+	}
+
+	return dropTokenUsers(r.ctx, r.lc)
+}
+
+func (r *roles) CreatePermission(req *serializers.PermissionReq) (*domain.Permission, *errors.RestErr) {
+	return r.rrepo.SavePermission(&domain.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+}
+
+func (r *roles) GetPermissions() (domain.Permissions, *errors.RestErr) {
+	return r.rrepo.GetPermissions()
+}
+
+func (r *roles) GetPermission(id uint) (*domain.Permission, *errors.RestErr) {
+	return r.rrepo.GetPermissionByID(id)
+}
+
+// UpdatePermission changes a permission, the built-in ones are checked by the routes so
+// they can't be renamed
+func (r *roles) UpdatePermission(id uint, req *serializers.PermissionReq) *errors.RestErr {
+	permission, getErr := r.rrepo.GetPermissionByID(id)
+	if getErr != nil {
+		return getErr
+	}
+
+	if isBuiltInPermission(permission.Name) && permission.Name != req.Name {
+		return errors.NewConflictError("built-in permissions can't be renamed")
+	}
+
+	if updateErr := r.rrepo.UpdatePermission(&domain.Permission{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+	}); updateErr != nil {
+		return updateErr
+	}
+
+	return dropTokenUsers(r.ctx, r.lc)
+}
+
+// DeletePermission removes a permission from every role, the built-in ones can't be removed
+func (r *roles) DeletePermission(id uint) *errors.RestErr {
+	permission, getErr := r.rrepo.GetPermissionByID(id)
+	if getErr != nil {
+		return getErr
+	}
+
+	if isBuiltInPermission(permission.Name) {
+		return errors.NewConflictError("built-in permissions can't be deleted")
+	}
+
+	if deleteErr := r.rrepo.DeletePermission(id); deleteErr != nil {
+		return deleteErr
+	}
+
+	return dropTokenUsers(r.ctx, r.lc)
+}
+
+// dropTokenUsers clears the cached permissions of every user, they're loaded again on the next request
+func dropTokenUsers(ctx context.Context, lc logger.LogClient) *errors.RestErr {
+	if err := cache.Client().DelPattern(ctx, config.Cache().Redis.TokenPrefix+"*"); err != nil {
+		lc.Error("error occurred when deleting cached token users", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func isBuiltInPermission(name string) bool {
+	for _, names := range consts.RolePermissions {
+		for _, builtIn := range names {
+			if builtIn == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	var resp []uint
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			resp = append(resp, id)
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,110 @@
+package impl
+
+import (
+	"context"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+)
+
+type stores struct {
+	ctx    context.Context
+	lc     logger.LogClient
+	strepo repository.IStores
+	locSvc svc.ILocations
+	uSvc   svc.IUsers
+}
+
+func NewStoresService(ctx context.Context, lc logger.LogClient, strepo repository.IStores, locSvc svc.ILocations, uSvc svc.IUsers) svc.IStores {
+	return &stores{
+		ctx:    ctx,
+		lc:     lc,
+		strepo: strepo,
+		locSvc: locSvc,
+		uSvc:   uSvc,
+	}
+}
+
+// CreateStore creates a store of the user, the store is shared with the company of the user
+func (s *stores) CreateStore(userID uint, req *serializers.StoreReq) (*domain.Store, *errors.RestErr) {
+	if locErr := s.locSvc.ValidateLocation(req.CityID, req.ZoneID, req.AreaID); locErr != nil {
+		return nil, locErr
+	}
+
+	scope, scopeErr := tenantScope(s.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	return s.strepo.SaveStore(&domain.Store{
+		UserID:       userID,
+		CompanyID:    scope.CompanyID,
+		Name:         req.Name,
+		ContactPhone: req.ContactPhone,
+		Address:      req.Address,
+		CityID:       req.CityID,
+		ZoneID:       req.ZoneID,
+		AreaID:       req.AreaID,
+	})
+}
+
+func (s *stores) GetStores(userID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	scope, scopeErr := tenantScope(s.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	result, err := s.strepo.GetStores(scope, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.Results = result
+	return filters, nil
+}
+
+// GetStore returns the store only within the tenant of the user, others get a not found
+func (s *stores) GetStore(userID, id uint) (*domain.Store, *errors.RestErr) {
+	scope, scopeErr := tenantScope(s.uSvc, userID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+
+	return s.strepo.GetStoreByID(scope, id)
+}
+
+// GetStoresByIDs returns the stores of the ids within the scope, the others are left out
+func (s *stores) GetStoresByIDs(scope *domain.TenantScope, ids []uint) (domain.Stores, *errors.RestErr) {
+	return s.strepo.GetStoresByIDs(scope, ids)
+}
+
+func (s *stores) UpdateStore(userID, id uint, req *serializers.StoreReq) *errors.RestErr {
+	if _, getErr := s.GetStore(userID, id); getErr != nil {
+		return getErr
+	}
+
+	if locErr := s.locSvc.ValidateLocation(req.CityID, req.ZoneID, req.AreaID); locErr != nil {
+		return locErr
+	}
+
+	return s.strepo.UpdateStore(&domain.Store{
+		ID:           id,
+		Name:         req.Name,
+		ContactPhone: req.ContactPhone,
+		Address:      req.Address,
+		CityID:       req.CityID,
+		ZoneID:       req.ZoneID,
+		AreaID:       req.AreaID,
+	})
+}
+
+func (s *stores) DeleteStore(userID, id uint) *errors.RestErr {
+	if _, getErr := s.GetStore(userID, id); getErr != nil {
+		return getErr
+	}
+
+	return s.strepo.DeleteStore(id)
+}
@@ -9,10 +9,12 @@ import (
 	"next-oms/infra/config"
 	"next-oms/infra/conn/cache"
 	"next-oms/infra/errors"
+	"next-oms/infra/jwtkeys"
 	"next-oms/infra/logger"
 	"strconv"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
@@ -30,12 +32,18 @@ func NewTokenService(ctx context.Context, lc logger.LogClient, urepo repository.
 	}
 }
 
-func (t *token) CreateToken(userID uint) (*serializers.JwtToken, error) {
+// CreateToken creates an access & refresh token pair, an empty familyID starts a new token family
+func (t *token) CreateToken(userID uint, familyID string) (*serializers.JwtToken, error) {
 	var err error
 	jwtConf := config.Jwt()
 	token := &serializers.JwtToken{}
 
+	if familyID == "" {
+		familyID = uuid.New().String()
+	}
+
 	token.UserID = userID
+	token.FamilyID = familyID
 	token.AccessExpiry = time.Now().Add(time.Minute * jwtConf.AccessTokenExpiry).Unix()
 	token.AccessUuid = uuid.New().String()
 
@@ -51,10 +59,10 @@ func (t *token) CreateToken(userID uint) (*serializers.JwtToken, error) {
 	atClaims["uid"] = user.ID
 	atClaims["aid"] = token.AccessUuid
 	atClaims["rid"] = token.RefreshUuid
+	atClaims["fid"] = token.FamilyID
 	atClaims["exp"] = token.AccessExpiry
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-	token.AccessToken, err = at.SignedString([]byte(jwtConf.AccessTokenSecret))
+	token.AccessToken, err = jwtkeys.Sign(atClaims)
 	if err != nil {
 		t.lc.Error(err.Error(), err)
 		return nil, errors.ErrAccessTokenSign
@@ -64,6 +72,7 @@ func (t *token) CreateToken(userID uint) (*serializers.JwtToken, error) {
 	rtClaims["uid"] = user.ID
 	rtClaims["aid"] = token.AccessUuid
 	rtClaims["rid"] = token.RefreshUuid
+	rtClaims["fid"] = token.FamilyID
 	rtClaims["exp"] = token.RefreshExpiry
 
 	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims)
@@ -76,7 +85,9 @@ func (t *token) CreateToken(userID uint) (*serializers.JwtToken, error) {
 	return token, nil
 }
 
-func (t *token) StoreTokenUuid(userID uint, token *serializers.JwtToken) error {
+// StoreTokenUuid stores the uuids of the tokens & records the tokens as the latest of their family,
+// the family is the session of the client the tokens were issued to
+func (t *token) StoreTokenUuid(userID uint, token *serializers.JwtToken, client *serializers.ClientInfo) error {
 	now := time.Now().Unix()
 	key, _ := strconv.Atoi(strconv.Itoa(int(userID)))
 
@@ -98,9 +109,127 @@ func (t *token) StoreTokenUuid(userID uint, token *serializers.JwtToken) error {
 		return err
 	}
 
-	return nil
+	// the family points to the latest tokens & lives as long as the latest refresh_token
+	family, err := t.GetTokenFamily(token.FamilyID)
+	if err == redis.Nil {
+		family = &serializers.TokenFamily{ID: token.FamilyID, CreatedAt: now}
+	} else if err != nil {
+		return err
+	}
+
+	family.UserID = userID
+	family.AccessUuid = token.AccessUuid
+	family.RefreshUuid = token.RefreshUuid
+	family.IP = client.IP
+	family.UserAgent = client.UserAgent
+	family.LastUsedAt = now
+
+	err = cache.Client().Set(
+		t.ctx,
+		config.Cache().Redis.TokenFamilyPrefix+token.FamilyID,
+		family, int(token.RefreshExpiry-now),
+	)
+	if err != nil {
+		return err
+	}
+
+	return cache.Client().SAdd(t.ctx, sessionsKey(userID), int(token.RefreshExpiry-now), token.FamilyID)
 }
 
 func (t *token) DeleteTokenUuid(uuid ...string) error {
 	return cache.Client().Del(t.ctx, uuid...)
 }
+
+// GetTokenFamily returns redis.Nil when the family was revoked or has expired
+func (t *token) GetTokenFamily(familyID string) (*serializers.TokenFamily, error) {
+	var family *serializers.TokenFamily
+
+	if err := cache.Client().GetStruct(t.ctx, config.Cache().Redis.TokenFamilyPrefix+familyID, &family); err != nil {
+		return nil, err
+	}
+
+	return family, nil
+}
+
+// GetTokenFamilies returns the live token families of the user, the expired ones are forgotten
+func (t *token) GetTokenFamilies(userID uint) ([]*serializers.TokenFamily, error) {
+	familyIDs, err := cache.Client().SMembers(t.ctx, sessionsKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var families []*serializers.TokenFamily
+	var expired []string
+
+	for _, familyID := range familyIDs {
+		family, err := t.GetTokenFamily(familyID)
+		if err == redis.Nil {
+			expired = append(expired, familyID)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		families = append(families, family)
+	}
+
+	if len(expired) > 0 {
+		if err := cache.Client().SRem(t.ctx, sessionsKey(userID), expired...); err != nil {
+			t.lc.Error("error occur when forgetting expired token families", err)
+		}
+	}
+
+	return families, nil
+}
+
+// RevokeTokenFamily drops the family along with its latest tokens, so neither of them works anymore
+func (t *token) RevokeTokenFamily(familyID string) error {
+	family, err := t.GetTokenFamily(familyID)
+	if err == redis.Nil {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	redisConf := config.Cache().Redis
+
+	err = cache.Client().Del(
+		t.ctx,
+		redisConf.AccessUuidPrefix+family.AccessUuid,
+		redisConf.RefreshUuidPrefix+family.RefreshUuid,
+		redisConf.TokenFamilyPrefix+familyID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return cache.Client().SRem(t.ctx, sessionsKey(family.UserID), familyID)
+}
+
+// RevokeTokenFamilies revokes every token family of the user but the one to keep, which may be empty
+func (t *token) RevokeTokenFamilies(userID uint, keepFamilyID string) error {
+	families, err := t.GetTokenFamilies(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		if family.ID == keepFamilyID {
+			continue
+		}
+
+		if err := t.RevokeTokenFamily(family.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sessionsKey(userID uint) string {
+	return config.Cache().Redis.SessionPrefix + strconv.Itoa(int(userID))
+}
@@ -0,0 +1,247 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"next-oms/app/domain"
+	"next-oms/app/repository"
+	"next-oms/app/serializers"
+	"next-oms/app/svc"
+	"next-oms/app/utils/totputil"
+	"next-oms/infra/config"
+	"next-oms/infra/conn/cache"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type twoFactor struct {
+	ctx   context.Context
+	lc    logger.LogClient
+	urepo repository.IUsers
+	rrepo repository.IRoles
+}
+
+func NewTwoFactorService(ctx context.Context, lc logger.LogClient, urepo repository.IUsers, rrepo repository.IRoles) svc.ITwoFactor {
+	return &twoFactor{
+		ctx:   ctx,
+		lc:    lc,
+		urepo: urepo,
+		rrepo: rrepo,
+	}
+}
+
+// Enroll starts the enrollment of the user, two factor is turned on once a code of the secret
+// is confirmed
+func (t *twoFactor) Enroll(userID uint) (*serializers.TwoFactorEnrollResp, *errors.RestErr) {
+	user, getErr := t.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	if user.TwoFactorAt != nil {
+		return nil, errors.NewConflictError("two factor authentication is already enabled")
+	}
+
+	secret, uri, err := startTwoFactorEnrollment(t.urepo, user)
+	if err != nil {
+		t.lc.Error("error occurred when starting two factor enrollment", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &serializers.TwoFactorEnrollResp{
+		Secret:     secret,
+		OtpauthUri: uri,
+	}, nil
+}
+
+// Confirm turns two factor on with the first code of the authenticator app
+func (t *twoFactor) Confirm(userID uint, req *serializers.TwoFactorCodeReq) (*serializers.BackupCodesResp, *errors.RestErr) {
+	user, getErr := t.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	if user.TwoFactorAt != nil {
+		return nil, errors.NewConflictError("two factor authentication is already enabled")
+	}
+
+	if user.TotpSecret == nil {
+		return nil, errors.NewBadRequestError("two factor enrollment wasn't started")
+	}
+
+	if !verifyTotp(t.ctx, user, req.Code) {
+		return nil, errors.NewBadRequestError(errors.ErrInvalidTwoFactorCode.Error())
+	}
+
+	codes, err := enableTwoFactor(t.urepo, user.ID)
+	if err != nil {
+		t.lc.Error("error occurred when enabling two factor", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	t.lc.Info("two factor enabled for user " + strconv.Itoa(int(user.ID)))
+
+	return &serializers.BackupCodesResp{BackupCodes: codes}, nil
+}
+
+// Disable turns two factor off, users whose role requires it can't
+func (t *twoFactor) Disable(userID uint, req *serializers.TwoFactorDisableReq) *errors.RestErr {
+	user, getErr := t.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return getErr
+	}
+
+	if user.TwoFactorAt == nil {
+		return errors.NewBadRequestError("two factor authentication isn't enabled")
+	}
+
+	role, roleErr := t.rrepo.GetRoleByID(user.RoleID)
+	if roleErr != nil {
+		return roleErr
+	}
+
+	if role.RequireTwoFactor {
+		return errors.NewForbiddenError("two factor authentication is required for your role")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.Password)); err != nil {
+		return errors.NewBadRequestError("password is incorrect")
+	}
+
+	if ok, err := verifyTwoFactorCode(t.ctx, t.urepo, user, req.Code); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	} else if !ok {
+		return errors.NewBadRequestError(errors.ErrInvalidTwoFactorCode.Error())
+	}
+
+	if disableErr := t.urepo.DisableUserTwoFactor(user.ID); disableErr != nil {
+		return disableErr
+	}
+
+	t.lc.Info("two factor disabled for user " + strconv.Itoa(int(user.ID)))
+	return nil
+}
+
+// RegenerateBackupCodes replaces the backup codes of the user, for when they ran out or leaked
+func (t *twoFactor) RegenerateBackupCodes(userID uint, req *serializers.TwoFactorCodeReq) (*serializers.BackupCodesResp, *errors.RestErr) {
+	user, getErr := t.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	if user.TwoFactorAt == nil {
+		return nil, errors.NewBadRequestError("two factor authentication isn't enabled")
+	}
+
+	if !verifyTotp(t.ctx, user, req.Code) {
+		return nil, errors.NewBadRequestError(errors.ErrInvalidTwoFactorCode.Error())
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		t.lc.Error("error occurred when generating backup codes", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if setErr := t.urepo.SetUserBackupCodes(user.ID, hashes); setErr != nil {
+		return nil, setErr
+	}
+
+	return &serializers.BackupCodesResp{BackupCodes: codes}, nil
+}
+
+// startTwoFactorEnrollment stores a new pending secret for the user
+func startTwoFactorEnrollment(urepo repository.IUsers, user *domain.User) (string, string, error) {
+	secret, err := totputil.NewSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if setErr := urepo.SetUserTotpSecret(user.ID, secret); setErr != nil {
+		return "", "", errors.NewError(setErr.Message)
+	}
+
+	return secret, totputil.URI(config.TwoFactor().Issuer, user.Email, secret), nil
+}
+
+// enableTwoFactor turns two factor on for the pending secret & returns the new backup codes
+func enableTwoFactor(urepo repository.IUsers, userID uint) ([]string, error) {
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if enableErr := urepo.EnableUserTwoFactor(userID, hashes); enableErr != nil {
+		return nil, errors.NewError(enableErr.Message)
+	}
+
+	return codes, nil
+}
+
+// verifyTwoFactorCode accepts a totp code or an unused backup code of the user
+func verifyTwoFactorCode(ctx context.Context, urepo repository.IUsers, user *domain.User, code string) (bool, error) {
+	if verifyTotp(ctx, user, code) {
+		return true, nil
+	}
+
+	if len(code) == totputil.Digits {
+		return false, nil
+	}
+
+	return urepo.UseBackupCode(user.ID, hashBackupCode(code))
+}
+
+// verifyTotp checks the code against the secret of the user, a code is accepted only once
+func verifyTotp(ctx context.Context, user *domain.User, code string) bool {
+	if user.TotpSecret == nil {
+		return false
+	}
+
+	step, ok := totputil.Validate(*user.TotpSecret, strings.TrimSpace(code), time.Now(), config.TwoFactor().Skew)
+	if !ok {
+		return false
+	}
+
+	// a code stays valid for the steps of the skew, remember it till then
+	ttl := (2*config.TwoFactor().Skew + 1) * totputil.Period
+	key := config.Cache().Redis.TotpUsedPrefix + strconv.Itoa(int(user.ID)) + "_" + strconv.FormatInt(step, 10)
+
+	fresh, err := cache.Client().SetNX(ctx, key, 1, ttl)
+	return err == nil && fresh
+}
+
+// newBackupCodes generates the backup codes as xxxxx-xxxxx along with their hashes
+func newBackupCodes() ([]string, []string, error) {
+	var codes, hashes []string
+
+	for i := 0; i < config.TwoFactor().BackupCodes; i++ {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(random))[:10]
+		code = code[:5] + "-" + code[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashBackupCode hashes the code as typed, case & dashes aside
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
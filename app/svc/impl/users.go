@@ -2,18 +2,27 @@ package impl
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"net/http"
 	"next-oms/app/domain"
 	"next-oms/app/repository"
 	"next-oms/app/serializers"
 	"next-oms/app/svc"
+	"next-oms/app/utils/consts"
 	"next-oms/app/utils/methodsutil"
 	"next-oms/app/utils/msgutil"
+	"next-oms/app/utils/passwordutil"
 	"next-oms/infra/config"
 	"next-oms/infra/conn/cache"
 	"next-oms/infra/errors"
 	"next-oms/infra/logger"
 	"strconv"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -22,29 +31,47 @@ type users struct {
 	ctx   context.Context
 	lc    logger.LogClient
 	urepo repository.IUsers
+	crepo repository.ICompanies
+	rrepo repository.IRoles
+	store domain.IStorage
+	msvc  svc.IMails
+	tSvc  svc.IToken
 }
 
-func NewUsersService(ctx context.Context, lc logger.LogClient, urepo repository.IUsers) svc.IUsers {
+func NewUsersService(ctx context.Context, lc logger.LogClient, urepo repository.IUsers, crepo repository.ICompanies, rrepo repository.IRoles, store domain.IStorage, msvc svc.IMails, tSvc svc.IToken) svc.IUsers {
 	return &users{
 		ctx:   ctx,
 		lc:    lc,
 		urepo: urepo,
+		crepo: crepo,
+		rrepo: rrepo,
+		store: store,
+		msvc:  msvc,
+		tSvc:  tSvc,
 	}
 }
 
-func (u *users) CreateAdminUser(user domain.User) (*domain.User, *errors.RestErr) {
-	resp, saveErr := u.urepo.SaveUser(&user)
-	if saveErr != nil {
-		return nil, saveErr
+// CreateUser signs a user up, the password has to meet the password policy
+func (u *users) CreateUser(user domain.User) (*domain.User, *errors.RestErr) {
+	if hashErr := u.hashNewUserPassword(&user); hashErr != nil {
+		return nil, hashErr
 	}
-	return resp, nil
-}
 
-func (u *users) CreateUser(user domain.User) (*domain.User, *errors.RestErr) {
+	// signup always makes a merchant without a company, both are granted by admins
+	user.RoleID = consts.RoleMerchantID
+	user.CompanyID = nil
+	user.VerifiedAt = nil
+
 	resp, saveErr := u.urepo.SaveUser(&user)
 	if saveErr != nil {
 		return nil, saveErr
 	}
+
+	// the user can ask for the link again, signup doesn't fail because of the mail
+	if err := u.sendVerification(resp); err != nil {
+		u.lc.Error("error occurred when sending verification mail to "+resp.Email, err)
+	}
+
 	return resp, nil
 }
 
@@ -56,6 +83,37 @@ func (u *users) GetUserById(userId uint) (*domain.User, *errors.RestErr) {
 	return resp, nil
 }
 
+// GetTokenUser returns the user along with the company, roles & permissions, it's cached
+// under the same key as the verify token response and dropped with the user cache
+func (u *users) GetTokenUser(userID uint) (*serializers.VerifyTokenResp, *errors.RestErr) {
+	var resp *serializers.VerifyTokenResp
+	tokenCacheKey := config.Cache().Redis.TokenPrefix + strconv.Itoa(int(userID))
+
+	if err := cache.Client().GetStruct(u.ctx, tokenCacheKey, &resp); err == nil {
+		return resp, nil
+	}
+
+	user, getErr := u.urepo.GetTokenUser(userID)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	if user.ID == 0 {
+		return nil, errors.NewUnauthorizedError("user has no role assigned")
+	}
+
+	if err := methodsutil.StructToStruct(user, &resp); err != nil {
+		u.lc.Error(msgutil.EntityStructToStructFailedMsg("set intermediate user to verify token response"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if err := cache.Client().Set(u.ctx, tokenCacheKey, resp, 0); err != nil {
+		u.lc.Error("setting user data on redis key", err)
+	}
+
+	return resp, nil
+}
+
 func (u *users) GetUserByEmail(userName string) (*domain.User, error) {
 	resp, getErr := u.urepo.GetUserByEmail(userName)
 	if getErr != nil {
@@ -64,6 +122,8 @@ func (u *users) GetUserByEmail(userName string) (*domain.User, error) {
 	return resp, nil
 }
 
+// UpdateUser updates the profile of the user, a new email has to be verified again so the user
+// gets a verification link & can't log in until it's used
 func (u *users) UpdateUser(userID uint, req serializers.UserReq) *errors.RestErr {
 	var user domain.User
 
@@ -73,12 +133,39 @@ func (u *users) UpdateUser(userID uint, req serializers.UserReq) *errors.RestErr
 		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
+	current, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return getErr
+	}
+
+	emailChanged := user.Email != "" && user.Email != current.Email
+	if emailChanged {
+		if _, err := u.urepo.GetUserByEmail(user.Email); err == nil {
+			return errors.NewConflictError("email is already registered")
+		} else if err.Error() != errors.ErrRecordNotFound {
+			return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		}
+	}
+
+	// the email is changed along with its verification below
 	user.ID = userID
+	user.Email = ""
 
 	if updateErr := u.urepo.UpdateUser(&user); updateErr != nil {
 		return updateErr
 	}
 
+	if emailChanged {
+		if setErr := u.urepo.SetUserEmail(userID, req.Email); setErr != nil {
+			return setErr
+		}
+
+		current.Email = req.Email
+		if err := u.sendVerification(current); err != nil {
+			u.lc.Error("error occurred when sending verification mail to "+current.Email, err)
+		}
+	}
+
 	if err := u.deleteUserCache(int(userID)); err != nil {
 		restErr := errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 		return restErr
@@ -86,8 +173,9 @@ func (u *users) UpdateUser(userID uint, req serializers.UserReq) *errors.RestErr
 	return nil
 }
 
-func (u *users) ChangePassword(id int, data *serializers.ChangePasswordReq) error {
-	user, getErr := u.urepo.GetUserByID(uint(id))
+// ChangePassword sets the new password & logs the user out of every other session
+func (u *users) ChangePassword(loggedInUser *serializers.LoggedInUser, data *serializers.ChangePasswordReq) error {
+	user, getErr := u.urepo.GetUserByID(uint(loggedInUser.ID))
 	if getErr != nil {
 		return errors.NewError(getErr.Message)
 	}
@@ -98,16 +186,90 @@ func (u *users) ChangePassword(id int, data *serializers.ChangePasswordReq) erro
 		return errors.ErrInvalidPassword
 	}
 
-	hashedPass, _ := bcrypt.GenerateFromPassword([]byte(data.NewPassword), 8)
+	if err := u.checkNewPassword(user, data.NewPassword); err != nil {
+		return err
+	}
 
-	updates := map[string]interface{}{
-		"password":    hashedPass,
-		"first_login": false,
+	if err := u.setPassword(user.ID, data.NewPassword); err != nil {
+		return err
 	}
 
-	upErr := u.urepo.UpdatePassword(user.ID, 1, updates)
-	if upErr != nil {
-		return errors.NewError(upErr.Message)
+	if err := u.tSvc.RevokeTokenFamilies(user.ID, loggedInUser.FamilyID); err != nil {
+		u.lc.Error("error occur when revoking sessions after password change", err)
+		return err
+	}
+
+	return nil
+}
+
+// hashNewUserPassword applies the password policy to the password of a new user & replaces it
+// with its hash
+func (u *users) hashNewUserPassword(user *domain.User) *errors.RestErr {
+	if user.Password == nil || *user.Password == "" {
+		return errors.NewBadRequestError("password is required")
+	}
+
+	if err := passwordutil.Check(*user.Password, user.Email, user.UserName, user.FirstName, user.LastName); err != nil {
+		return errors.NewPasswordPolicyError(err.(*errors.PasswordPolicyError).Violations)
+	}
+
+	hashedPass, err := passwordutil.Hash(*user.Password)
+	if err != nil {
+		u.lc.Error("error occur when hashing password", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	password := string(hashedPass)
+	now := time.Now()
+
+	user.Password = &password
+	user.PasswordChangedAt = &now
+
+	return nil
+}
+
+// checkNewPassword applies the password policy to a new password of the user, the current one &
+// the ones of the password history can't be used again
+func (u *users) checkNewPassword(user *domain.User, password string) error {
+	if err := passwordutil.Check(password, user.Email, user.UserName, user.FirstName, user.LastName); err != nil {
+		return err
+	}
+
+	historySize := config.Password().HistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	hashes, getErr := u.urepo.GetPasswordHistory(user.ID, historySize)
+	if getErr != nil {
+		return errors.NewError(getErr.Message)
+	}
+
+	// the users from before the password history only have the current password
+	if user.Password != nil {
+		hashes = append(hashes, *user.Password)
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return &errors.PasswordPolicyError{
+				Violations: []string{fmt.Sprintf("must not be one of your last %d passwords", historySize)},
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *users) setPassword(userID uint, password string) error {
+	hashedPass, err := passwordutil.Hash(password)
+	if err != nil {
+		u.lc.Error("error occur when hashing password", err)
+		return err
+	}
+
+	if setErr := u.urepo.SetUserPassword(userID, hashedPass, config.Password().HistorySize); setErr != nil {
+		return errors.NewError(setErr.Message)
 	}
 
 	return nil
@@ -119,72 +281,409 @@ func (u *users) ForgotPassword(email string) error {
 		return err
 	}
 
+	return u.sendPasswordReset(user)
+}
+
+// sendPasswordReset mails the user a link to set a new password
+func (u *users) sendPasswordReset(user *domain.User) error {
+	signedToken, err := u.passwordResetToken(user, config.Jwt().ResetTokenExpiry)
+	if err != nil {
+		return err
+	}
+
+	fpassReq := &serializers.ForgetPasswordMailReq{
+		To:        user.Email,
+		FirstName: user.FirstName,
+		UserID:    user.ID,
+		Token:     signedToken,
+	}
+
+	if err := u.msvc.SendForgotPasswordEmail(*fpassReq); err != nil {
+		return errors.ErrSendingEmail
+	}
+
+	return nil
+}
+
+// passwordResetToken signs a reset token valid for expiry minutes, the jti of the token is kept in
+// redis until it's used or expires so every token can reset the password only once
+func (u *users) passwordResetToken(user *domain.User, expiry time.Duration) (string, error) {
 	secret := passwordResetSecret(user)
+	jti := uuid.New().String()
+	ttl := time.Minute * expiry
 
 	payload := jwt.MapClaims{}
 	payload["email"] = user.Email
+	payload["jti"] = jti
+	payload["exp"] = time.Now().Add(ttl).Unix()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	signedToken, err := token.SignedString([]byte(secret))
 
 	if err != nil {
 		u.lc.Error("error occur when getting complete signed token", err)
+		return "", err
+	}
+
+	key := config.Cache().Redis.ResetTokenPrefix + jti
+	if err := cache.Client().Set(u.ctx, key, user.ID, int(ttl.Seconds())); err != nil {
+		u.lc.Error("error occur when storing reset token jti", err)
+		return "", err
+	}
+
+	return signedToken, nil
+}
+
+func (u *users) VerifyResetPassword(req *serializers.VerifyResetPasswordReq) error {
+	_, err := u.verifyResetToken(req.ID, req.Token)
+	return err
+}
+
+// ResetPassword sets the new password if the reset token is valid, the token is used up even
+// when the password can't be saved, but not when the password breaks the password policy
+func (u *users) ResetPassword(req *serializers.ResetPasswordReq) error {
+	jti, err := u.verifyResetToken(req.ID, req.Token)
+	if err != nil {
+		return err
+	}
+
+	user, getErr := u.urepo.GetUserByID(uint(req.ID))
+	if getErr != nil {
+		return errors.ErrInvalidPasswordResetToken
+	}
+
+	if err := u.checkNewPassword(user, req.Password); err != nil {
+		return err
+	}
+
+	consumed, err := cache.Client().Consume(u.ctx, config.Cache().Redis.ResetTokenPrefix+jti)
+	if err != nil {
+		u.lc.Error("error occur when consuming reset token jti", err)
+		return err
+	}
+
+	if !consumed {
+		return errors.ErrInvalidPasswordResetToken
+	}
+
+	if err := u.setPassword(user.ID, req.Password); err != nil {
 		return err
 	}
 
-	// TODO: Send Mail
-	u.lc.Info(signedToken)
-	// fpassReq := &serializers.ForgetPasswordMailReq{
-	// 	To:     user.Email,
-	// 	UserID: user.ID,
-	// 	Token:  signedToken,
-	// }
+	// whoever knew the old password is logged out
+	if err := u.tSvc.RevokeTokenFamilies(uint(req.ID), ""); err != nil {
+		u.lc.Error("error occur when revoking sessions after password reset", err)
+		return err
+	}
 
-	// if err := u.msvc.SendForgotPasswordEmail(*fpassReq); err != nil {
-	// 	return errors.ErrSendingEmail
-	// }
+	// the reset link went to the email, so the email is verified as well
+	if verifyErr := u.urepo.SetUserVerified(uint(req.ID)); verifyErr != nil {
+		return errors.NewError(verifyErr.Message)
+	}
 
 	return nil
 }
 
-func (u *users) VerifyResetPassword(req *serializers.VerifyResetPasswordReq) error {
-	user, getErr := u.urepo.GetUserByID(uint(req.ID))
+// VerifyEmail verifies the email of the user the verification token was issued to
+func (u *users) VerifyEmail(verifyToken string) error {
+	parsedToken, err := methodsutil.ParseJwtToken(verifyToken, config.Jwt().VerifyTokenSecret)
+	if err != nil {
+		u.lc.Error("error occur when parse verification token", err)
+		return errors.ErrParseJwt
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return errors.ErrInvalidVerifyToken
+	}
+
+	userID, _ := claims["sub"].(float64)
+	email, _ := claims["email"].(string)
+
+	user, getErr := u.urepo.GetUserByID(uint(userID))
 	if getErr != nil {
+		if getErr.Status == http.StatusNotFound {
+			return errors.ErrInvalidVerifyToken
+		}
 		return errors.NewError(getErr.Message)
 	}
 
+	// the email may have changed after the token was issued
+	if user.Email != email {
+		return errors.ErrInvalidVerifyToken
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	if verifyErr := u.urepo.SetUserVerified(user.ID); verifyErr != nil {
+		return errors.NewError(verifyErr.Message)
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new verification link, unknown or verified emails are ignored so the
+// response doesn't tell which emails are registered. A link is sent at most once per VerifyResendTtl
+func (u *users) ResendVerification(email string) error {
+	user, err := u.urepo.GetUserByEmail(email)
+	if err != nil {
+		if err.Error() == errors.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	redisConf := config.Cache().Redis
+	sent, err := cache.Client().SetNX(u.ctx, redisConf.VerifyResendPrefix+strconv.Itoa(int(user.ID)), true, redisConf.VerifyResendTtl)
+	if err != nil {
+		u.lc.Error("error occur when throttling verification mail", err)
+		return err
+	}
+
+	if !sent {
+		return nil
+	}
+
+	return u.sendVerification(user)
+}
+
+func (u *users) sendVerification(user *domain.User) error {
+	payload := jwt.MapClaims{}
+	payload["sub"] = user.ID
+	payload["email"] = user.Email
+	payload["exp"] = time.Now().Add(time.Minute * config.Jwt().VerifyTokenExpiry).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	signedToken, err := token.SignedString([]byte(config.Jwt().VerifyTokenSecret))
+	if err != nil {
+		u.lc.Error("error occur when signing verification token", err)
+		return err
+	}
+
+	return u.msvc.SendVerificationEmail(serializers.VerificationMailReq{
+		To:        user.Email,
+		FirstName: user.FirstName,
+		Token:     signedToken,
+	})
+}
+
+// verifyResetToken checks the signature, expiry & email of the reset token and that its jti
+// wasn't used yet, returns the jti
+func (u *users) verifyResetToken(userID int, resetToken string) (string, error) {
+	user, getErr := u.urepo.GetUserByID(uint(userID))
+	if getErr != nil {
+		if getErr.Status == http.StatusNotFound {
+			return "", errors.ErrInvalidPasswordResetToken
+		}
+		return "", errors.NewError(getErr.Message)
+	}
+
 	secret := passwordResetSecret(user)
 
-	parsedToken, err := methodsutil.ParseJwtToken(req.Token, secret)
+	parsedToken, err := methodsutil.ParseJwtToken(resetToken, secret)
 	if err != nil {
 		u.lc.Error("error occur when parse jwt token with secret", err)
-		return errors.ErrParseJwt
+		return "", errors.ErrParseJwt
 	}
 
-	if _, ok := parsedToken.Claims.(jwt.Claims); !ok && !parsedToken.Valid {
-		return errors.ErrInvalidPasswordResetToken
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return "", errors.ErrInvalidPasswordResetToken
 	}
 
-	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return errors.ErrInvalidPasswordResetToken
+	parsedEmail, _ := claims["email"].(string)
+	jti, _ := claims["jti"].(string)
+	if user.Email != parsedEmail || jti == "" {
+		return "", errors.ErrInvalidPasswordResetToken
 	}
 
-	parsedEmail := claims["email"].(string)
-	if user.Email != parsedEmail {
-		return errors.ErrInvalidPasswordResetToken
+	storedUserID, err := cache.Client().Get(u.ctx, config.Cache().Redis.ResetTokenPrefix+jti)
+	if err == redis.Nil {
+		return "", errors.ErrInvalidPasswordResetToken
 	}
 
-	return nil
+	if err != nil {
+		u.lc.Error("error occur when getting reset token jti", err)
+		return "", err
+	}
+
+	if storedUserID != strconv.Itoa(int(user.ID)) {
+		return "", errors.ErrInvalidPasswordResetToken
+	}
+
+	return jti, nil
 }
 
-func (u *users) ResetPassword(req *serializers.ResetPasswordReq) error {
-	hashedPass, _ := bcrypt.GenerateFromPassword([]byte(req.Password), 8)
+// GetCompanyUsers lists the users of a company, to the admins of the company & platform admins
+func (u *users) GetCompanyUsers(callerID, companyID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr) {
+	if authErr := u.authorizeCompany(callerID, companyID); authErr != nil {
+		return nil, authErr
+	}
+
+	result, getErr := u.urepo.GetUsersByCompany(companyID, filters)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	var resp []*serializers.UserResp
+	if err := methodsutil.StructToStruct(result, &resp); err != nil {
+		u.lc.Error(msgutil.EntityStructToStructFailedMsg("set company users"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+	return filters, nil
+}
+
+// InviteUser adds a new user to the company, the user gets a password reset link to set the password
+func (u *users) InviteUser(callerID, companyID uint, req *serializers.InviteUserReq) (*serializers.UserResp, *errors.RestErr) {
+	if authErr := u.authorizeCompany(callerID, companyID); authErr != nil {
+		return nil, authErr
+	}
+
+	if _, err := u.urepo.GetUserByEmail(req.Email); err == nil {
+		return nil, errors.NewConflictError("email is already registered")
+	} else if err.Error() != errors.ErrRecordNotFound {
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	password, err := unusablePassword()
+	if err != nil {
+		u.lc.Error("error occurred when generating invitation password", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
 
-	if err := u.urepo.ResetPassword(req.ID, hashedPass); err != nil {
+	user, saveErr := u.urepo.SaveUser(&domain.User{
+		UserName:  req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Password:  &password,
+		CompanyID: &companyID,
+		RoleID:    consts.RoleMerchantID,
+		Active:    true,
+	})
+	if saveErr != nil {
+		return nil, saveErr
+	}
+
+	if err := u.sendInvitation(user, companyID); err != nil {
+		u.lc.Error("error occurred when sending invitation to "+user.Email, err)
+	}
+
+	var resp serializers.UserResp
+	if err := methodsutil.StructToStruct(user, &resp); err != nil {
+		u.lc.Error(msgutil.EntityStructToStructFailedMsg("set invited user"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+// sendInvitation mails the invited user a link to set the password, it's the same link as a password reset
+func (u *users) sendInvitation(user *domain.User, companyID uint) error {
+	company, getErr := u.crepo.GetCompanyByID(companyID)
+	if getErr != nil {
+		return errors.NewError(getErr.Message)
+	}
+
+	signedToken, err := u.passwordResetToken(user, config.Jwt().InviteTokenExpiry)
+	if err != nil {
 		return err
 	}
 
+	return u.msvc.SendInvitationEmail(serializers.InvitationMailReq{
+		To:          user.Email,
+		FirstName:   user.FirstName,
+		CompanyName: company.Name,
+		UserID:      user.ID,
+		Token:       signedToken,
+	})
+}
+
+// AssignCompany moves a user to a company, a nil company takes the user out of its company
+func (u *users) AssignCompany(userID uint, companyID *uint) *errors.RestErr {
+	if companyID != nil {
+		if _, getErr := u.crepo.GetCompanyByID(*companyID); getErr != nil {
+			return getErr
+		}
+	}
+
+	if _, getErr := u.urepo.GetUserByID(userID); getErr != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if setErr := u.urepo.SetUserCompany(userID, companyID); setErr != nil {
+		return setErr
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// SetUserActive activates or deactivates a user of the company, a deactivated user can't log in
+// and the cached user is dropped so the current tokens stop working as well
+func (u *users) SetUserActive(callerID, companyID, userID uint, active bool) *errors.RestErr {
+	if authErr := u.authorizeCompany(callerID, companyID); authErr != nil {
+		return authErr
+	}
+
+	if callerID == userID {
+		return errors.NewConflictError("you can't change your own active status")
+	}
+
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil || user.CompanyID == nil || *user.CompanyID != companyID {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	return u.setUserActive(userID, active)
+}
+
+func (u *users) setUserActive(userID uint, active bool) *errors.RestErr {
+	if setErr := u.urepo.SetUserActive(userID, active); setErr != nil {
+		return setErr
+	}
+
+	if !active {
+		if err := u.tSvc.RevokeTokenFamilies(userID, ""); err != nil {
+			u.lc.Error("error occur when revoking sessions of deactivated user", err)
+			return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		}
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// authorizeCompany lets platform admins reach any company and others only their own company
+func (u *users) authorizeCompany(callerID, companyID uint) *errors.RestErr {
+	caller, getErr := u.GetTokenUser(callerID)
+	if getErr != nil {
+		return getErr
+	}
+
+	if caller.Admin || caller.SuperAdmin {
+		return nil
+	}
+
+	if caller.CompanyID == nil || uint(*caller.CompanyID) != companyID {
+		return errors.NewForbiddenError("you can only manage the users of your own company")
+	}
+
 	return nil
 }
 
@@ -201,6 +700,21 @@ func (u *users) deleteUserCache(userID int) error {
 	return nil
 }
 
+// unusablePassword hashes a random password nobody knows, it's only there until the user resets it
+func unusablePassword() (string, error) {
+	randomPass := make([]byte, 32)
+	if _, err := rand.Read(randomPass); err != nil {
+		return "", err
+	}
+
+	hashedPass, err := passwordutil.Hash(hex.EncodeToString(randomPass))
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedPass), nil
+}
+
 func passwordResetSecret(user *domain.User) string {
 	return *user.Password + strconv.Itoa(int(user.CreatedAt.Unix()))
 }
@@ -0,0 +1,236 @@
+package impl
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/methodsutil"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/errors"
+	"time"
+)
+
+// CreateAdminUser creates a verified user with the given role & company, the password has to
+// meet the password policy
+func (u *users) CreateAdminUser(callerID uint, user domain.User) (*domain.User, *errors.RestErr) {
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID); authErr != nil {
+		return nil, authErr
+	}
+
+	if _, err := u.urepo.GetUserByEmail(user.Email); err == nil {
+		return nil, errors.NewConflictError("email is already registered")
+	} else if err.Error() != errors.ErrRecordNotFound {
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if _, getErr := u.rrepo.GetRoleByID(user.RoleID); getErr != nil {
+		return nil, getErr
+	}
+
+	if user.CompanyID != nil {
+		if _, getErr := u.crepo.GetCompanyByID(*user.CompanyID); getErr != nil {
+			return nil, getErr
+		}
+	}
+
+	if hashErr := u.hashNewUserPassword(&user); hashErr != nil {
+		return nil, hashErr
+	}
+
+	now := time.Now()
+	user.VerifiedAt = &now
+	user.Active = true
+
+	resp, saveErr := u.urepo.SaveUser(&user)
+	if saveErr != nil {
+		return nil, saveErr
+	}
+	return resp, nil
+}
+
+// GetUsers lists the users of every company, deleted lists the soft deleted users instead
+func (u *users) GetUsers(filters *serializers.ListFilters, deleted bool) (*serializers.ListFilters, *errors.RestErr) {
+	result, getErr := u.urepo.GetUsers(filters, deleted)
+	if getErr != nil {
+		return nil, getErr
+	}
+
+	var resp []*serializers.UserResp
+	if err := methodsutil.StructToStruct(result, &resp); err != nil {
+		u.lc.Error(msgutil.EntityStructToStructFailedMsg("set users"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+	return filters, nil
+}
+
+// SetUserActiveByAdmin activates or deactivates a user of any company
+func (u *users) SetUserActiveByAdmin(callerID, userID uint, active bool) *errors.RestErr {
+	if callerID == userID {
+		return errors.NewConflictError("you can't change your own active status")
+	}
+
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID); authErr != nil {
+		return authErr
+	}
+
+	return u.setUserActive(userID, active)
+}
+
+// DeleteUser soft deletes a user & ends its sessions, the user can be restored with RestoreUser
+func (u *users) DeleteUser(callerID, userID uint) *errors.RestErr {
+	if callerID == userID {
+		return errors.NewConflictError("you can't delete yourself")
+	}
+
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID); authErr != nil {
+		return authErr
+	}
+
+	if delErr := u.urepo.DeleteUser(userID); delErr != nil {
+		return delErr
+	}
+
+	if err := u.tSvc.RevokeTokenFamilies(userID, ""); err != nil {
+		u.lc.Error("error occur when revoking sessions of deleted user", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// RestoreUser brings back a soft deleted user, unless its email was registered again meanwhile
+func (u *users) RestoreUser(callerID, userID uint) *errors.RestErr {
+	user, getErr := u.urepo.GetDeletedUser(userID)
+	if getErr != nil {
+		return errors.NewNotFoundError("deleted user not found")
+	}
+
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID); authErr != nil {
+		return authErr
+	}
+
+	if _, err := u.urepo.GetUserByEmail(user.Email); err == nil {
+		return errors.NewConflictError("email is registered to another user")
+	} else if err.Error() != errors.ErrRecordNotFound {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if restoreErr := u.urepo.RestoreUser(userID); restoreErr != nil {
+		return restoreErr
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// ForcePasswordReset replaces the password of the user with one nobody knows, ends its sessions
+// & mails it a link to set a new password
+func (u *users) ForcePasswordReset(callerID, userID uint) *errors.RestErr {
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID); authErr != nil {
+		return authErr
+	}
+
+	password, err := unusablePassword()
+	if err != nil {
+		u.lc.Error("error occurred when generating forced reset password", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	// the unusable password is kept out of the password history
+	if setErr := u.urepo.SetUserPassword(userID, []byte(password), 0); setErr != nil {
+		return setErr
+	}
+	user.Password = &password
+
+	if err := u.tSvc.RevokeTokenFamilies(userID, ""); err != nil {
+		u.lc.Error("error occur when revoking sessions of user on forced password reset", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	// the user can still ask for a link through forgot password if the mail doesn't go out
+	if err := u.sendPasswordReset(user); err != nil {
+		u.lc.Error("error occurred when sending forced password reset mail to "+user.Email, err)
+		return errors.NewInternalServerError("failed to send password reset email")
+	}
+
+	return nil
+}
+
+// AssignRole replaces the role of a user
+func (u *users) AssignRole(callerID, userID, roleID uint) *errors.RestErr {
+	if callerID == userID {
+		return errors.NewConflictError("you can't change your own role")
+	}
+
+	user, getErr := u.urepo.GetUserByID(userID)
+	if getErr != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	if authErr := u.authorizeUserAdmin(callerID, user.RoleID, roleID); authErr != nil {
+		return authErr
+	}
+
+	if _, getErr := u.rrepo.GetRoleByID(roleID); getErr != nil {
+		return getErr
+	}
+
+	if setErr := u.urepo.SetUserRole(userID, roleID); setErr != nil {
+		return setErr
+	}
+
+	if err := u.deleteUserCache(int(userID)); err != nil {
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// authorizeUserAdmin keeps the super admin role to super admins, only they can manage the super
+// admins or make a user one
+func (u *users) authorizeUserAdmin(callerID uint, roleIDs ...uint) *errors.RestErr {
+	caller, getErr := u.GetTokenUser(callerID)
+	if getErr != nil {
+		return getErr
+	}
+
+	if caller.SuperAdmin {
+		return nil
+	}
+
+	for _, roleID := range roleIDs {
+		if roleID == consts.RoleSuperAdminID {
+			return errors.NewForbiddenError("only super admins can manage super admins")
+		}
+	}
+
+	return nil
+}
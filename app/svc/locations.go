@@ -0,0 +1,31 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type ILocations interface {
+	CreateCity(req *serializers.CityReq) (*domain.City, *errors.RestErr)
+	GetCities() (domain.Cities, *errors.RestErr)
+	GetCity(id uint) (*domain.City, *errors.RestErr)
+	UpdateCity(id uint, req *serializers.CityReq) *errors.RestErr
+	DeleteCity(id uint) *errors.RestErr
+
+	CreateZone(req *serializers.ZoneReq) (*domain.Zone, *errors.RestErr)
+	GetZones(cityID uint) (domain.Zones, *errors.RestErr)
+	GetZone(id uint) (*domain.Zone, *errors.RestErr)
+	UpdateZone(id uint, req *serializers.ZoneReq) *errors.RestErr
+	DeleteZone(id uint) *errors.RestErr
+
+	CreateArea(req *serializers.AreaReq) (*domain.Area, *errors.RestErr)
+	GetAreas(zoneID uint) (domain.Areas, *errors.RestErr)
+	GetArea(id uint) (*domain.Area, *errors.RestErr)
+	UpdateArea(id uint, req *serializers.AreaReq) *errors.RestErr
+	DeleteArea(id uint) *errors.RestErr
+
+	GetZonesByIDs(ids []uint) (domain.Zones, *errors.RestErr)
+	GetAreasByIDs(ids []uint) (domain.Areas, *errors.RestErr)
+	ValidateLocation(cityID, zoneID, areaID uint) *errors.RestErr
+}
@@ -0,0 +1,10 @@
+package svc
+
+import "next-oms/app/serializers"
+
+type IMails interface {
+	SendForgotPasswordEmail(req serializers.ForgetPasswordMailReq) error
+	SendInvitationEmail(req serializers.InvitationMailReq) error
+	SendVerificationEmail(req serializers.VerificationMailReq) error
+	SendTwoFactorEnrollmentEmail(req serializers.TwoFactorEnrollmentMailReq) error
+}
@@ -1,12 +1,19 @@
 package svc
 
 import (
+	"next-oms/app/domain"
 	"next-oms/app/serializers"
 	"next-oms/infra/errors"
 )
 
 type IOrders interface {
-	CreateOrder(order *serializers.OrderReq) (*serializers.OrderResp, *errors.RestErr)
-	GetOrders(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
-	CancelOrder(conID string) *errors.RestErr
+	CreateOrder(userID uint, order *serializers.OrderReq) (*serializers.OrderResp, *errors.RestErr)
+	CreateOrders(userID uint, rows []*serializers.BulkOrderRow) *serializers.BulkOrderResp
+	QuoteOrder(userID uint, order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr)
+	GetOrders(userID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	GetOrder(userID uint, conID string) (*domain.Order, *errors.RestErr)
+	GetOrderByMerchantOrderID(userID uint, storeID int, merchantOrderID string) (*domain.Order, *errors.RestErr)
+	UpdateOrderStatus(conID string, userID uint, req *serializers.OrderStatusReq) *errors.RestErr
+	CancelOrder(conID string, userID uint, req *serializers.CancelOrderReq) *errors.RestErr
+	GetOrderHistory(userID uint, conID string) (domain.OrderHistories, *errors.RestErr)
 }
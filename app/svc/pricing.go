@@ -0,0 +1,18 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type IPricing interface {
+	CreateRateCard(req *serializers.RateCardReq) (*domain.RateCard, *errors.RestErr)
+	GetRateCards(filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	GetRateCard(id uint) (*domain.RateCard, *errors.RestErr)
+	UpdateRateCard(id uint, req *serializers.RateCardReq) (*domain.RateCard, *errors.RestErr)
+	DeleteRateCard(id uint) *errors.RestErr
+	GetActiveRateCard() (*domain.RateCard, *errors.RestErr)
+	PriceOrder(order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr)
+	PriceOrderWith(card *domain.RateCard, order *serializers.OrderReq) (*serializers.FeeBreakdown, *errors.RestErr)
+}
@@ -0,0 +1,21 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type IRoles interface {
+	CreateRole(req *serializers.RoleReq) (*domain.Role, *errors.RestErr)
+	GetRoles() (domain.Roles, *errors.RestErr)
+	GetRole(id uint) (*domain.Role, *errors.RestErr)
+	UpdateRole(id uint, req *serializers.RoleReq) *errors.RestErr
+	DeleteRole(id uint) *errors.RestErr
+	SetRolePermissions(id uint, req *serializers.RolePermissionsReq) *errors.RestErr
+	CreatePermission(req *serializers.PermissionReq) (*domain.Permission, *errors.RestErr)
+	GetPermissions() (domain.Permissions, *errors.RestErr)
+	GetPermission(id uint) (*domain.Permission, *errors.RestErr)
+	UpdatePermission(id uint, req *serializers.PermissionReq) *errors.RestErr
+	DeletePermission(id uint) *errors.RestErr
+}
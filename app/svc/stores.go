@@ -0,0 +1,16 @@
+package svc
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type IStores interface {
+	CreateStore(userID uint, req *serializers.StoreReq) (*domain.Store, *errors.RestErr)
+	GetStores(userID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	GetStore(userID, id uint) (*domain.Store, *errors.RestErr)
+	GetStoresByIDs(scope *domain.TenantScope, ids []uint) (domain.Stores, *errors.RestErr)
+	UpdateStore(userID, id uint, req *serializers.StoreReq) *errors.RestErr
+	DeleteStore(userID, id uint) *errors.RestErr
+}
@@ -5,7 +5,11 @@ import (
 )
 
 type IToken interface {
-	CreateToken(userID uint) (*serializers.JwtToken, error)
-	StoreTokenUuid(userID uint, token *serializers.JwtToken) error
+	CreateToken(userID uint, familyID string) (*serializers.JwtToken, error)
+	StoreTokenUuid(userID uint, token *serializers.JwtToken, client *serializers.ClientInfo) error
 	DeleteTokenUuid(uuid ...string) error
+	GetTokenFamily(familyID string) (*serializers.TokenFamily, error)
+	GetTokenFamilies(userID uint) ([]*serializers.TokenFamily, error)
+	RevokeTokenFamily(familyID string) error
+	RevokeTokenFamilies(userID uint, keepFamilyID string) error
 }
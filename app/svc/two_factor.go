@@ -0,0 +1,13 @@
+package svc
+
+import (
+	"next-oms/app/serializers"
+	"next-oms/infra/errors"
+)
+
+type ITwoFactor interface {
+	Enroll(userID uint) (*serializers.TwoFactorEnrollResp, *errors.RestErr)
+	Confirm(userID uint, req *serializers.TwoFactorCodeReq) (*serializers.BackupCodesResp, *errors.RestErr)
+	Disable(userID uint, req *serializers.TwoFactorDisableReq) *errors.RestErr
+	RegenerateBackupCodes(userID uint, req *serializers.TwoFactorCodeReq) (*serializers.BackupCodesResp, *errors.RestErr)
+}
@@ -8,11 +8,26 @@ import (
 
 type IUsers interface {
 	CreateUser(domain.User) (*domain.User, *errors.RestErr)
+	CreateAdminUser(callerID uint, user domain.User) (*domain.User, *errors.RestErr)
 	GetUserById(uid uint) (*domain.User, *errors.RestErr)
 	GetUserByEmail(useremail string) (*domain.User, error)
+	GetTokenUser(userID uint) (*serializers.VerifyTokenResp, *errors.RestErr)
+	GetCompanyUsers(callerID, companyID uint, filters *serializers.ListFilters) (*serializers.ListFilters, *errors.RestErr)
+	InviteUser(callerID, companyID uint, req *serializers.InviteUserReq) (*serializers.UserResp, *errors.RestErr)
+	AssignCompany(userID uint, companyID *uint) *errors.RestErr
+	SetUserActive(callerID, companyID, userID uint, active bool) *errors.RestErr
 	UpdateUser(userID uint, req serializers.UserReq) *errors.RestErr
-	ChangePassword(id int, data *serializers.ChangePasswordReq) error
+	UploadAvatar(userID uint, data []byte) (*serializers.AvatarResp, *errors.RestErr)
+	ChangePassword(user *serializers.LoggedInUser, data *serializers.ChangePasswordReq) error
 	ForgotPassword(email string) error
 	VerifyResetPassword(req *serializers.VerifyResetPasswordReq) error
 	ResetPassword(req *serializers.ResetPasswordReq) error
+	VerifyEmail(token string) error
+	ResendVerification(email string) error
+	GetUsers(filters *serializers.ListFilters, deleted bool) (*serializers.ListFilters, *errors.RestErr)
+	SetUserActiveByAdmin(callerID, userID uint, active bool) *errors.RestErr
+	DeleteUser(callerID, userID uint) *errors.RestErr
+	RestoreUser(callerID, userID uint) *errors.RestErr
+	ForcePasswordReset(callerID, userID uint) *errors.RestErr
+	AssignRole(callerID, userID, roleID uint) *errors.RestErr
 }
@@ -0,0 +1,8 @@
+package templates
+
+import "embed"
+
+// Mails holds the html & text bodies of the mails, <name>.html and <name>.txt
+//
+//go:embed mails
+var Mails embed.FS
@@ -0,0 +1,125 @@
+package conidutil
+
+import (
+	"next-oms/infra/errors"
+	"strings"
+	"time"
+)
+
+// Consignment ids look like 26101800000018, a yymmdd date prefix, a zero padded base32
+// sequence of the day and one check character. Ids sort by creation order as strings,
+// the alphabet is Crockford's base32 which is in ascii order and skips I, L, O and U
+const (
+	alphabet     = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base         = uint64(len(alphabet))
+	DateLayout   = "060102"
+	dateLength   = len(DateLayout)
+	seqLength    = 7
+	Length       = dateLength + seqLength + 1
+	MaxSequence  = uint64(1)<<(5*seqLength) - 1
+	invalidDigit = -1
+
+	// legacy ids look like CONS-12-John Doe-5577006791947779410, the store, the recipient name &
+	// a random number. They're still found by lookups, up to the size of the column
+	legacyPrefix    = "CONS-"
+	LegacyMaxLength = 191
+)
+
+var (
+	ErrInvalidConsignmentID = errors.NewError("invalid consignment id")
+	ErrSequenceOverflow     = errors.NewError("consignment sequence exhausted for the day")
+)
+
+// Prefix returns the date part of the ids generated at t
+func Prefix(t time.Time) string {
+	return t.Format(DateLayout)
+}
+
+// Generate builds the consignment id of the seq'th order of the day t
+func Generate(t time.Time, seq uint64) (string, error) {
+	if seq == 0 || seq > MaxSequence {
+		return "", ErrSequenceOverflow
+	}
+
+	encoded := make([]byte, seqLength)
+	for i := seqLength - 1; i >= 0; i-- {
+		encoded[i] = alphabet[seq%base]
+		seq /= base
+	}
+
+	body := Prefix(t) + string(encoded)
+
+	return body + string(alphabet[checkDigit(body)]), nil
+}
+
+// Validate checks the length, alphabet, date and check character of a consignment id
+func Validate(id string) error {
+	if len(id) != Length {
+		return ErrInvalidConsignmentID
+	}
+
+	body := strings.ToUpper(id[:Length-1])
+	for i := 0; i < len(body); i++ {
+		if digit(body[i]) == invalidDigit {
+			return ErrInvalidConsignmentID
+		}
+	}
+
+	if _, err := time.Parse(DateLayout, body[:dateLength]); err != nil {
+		return ErrInvalidConsignmentID
+	}
+
+	if digit(strings.ToUpper(id)[Length-1]) != checkDigit(body) {
+		return ErrInvalidConsignmentID
+	}
+
+	return nil
+}
+
+// ValidateLookup checks an id an order may be looked up by, unlike Validate it lets the legacy ids through
+func ValidateLookup(id string) error {
+	if strings.HasPrefix(id, legacyPrefix) && len(id) > len(legacyPrefix) && len(id) <= LegacyMaxLength {
+		return nil
+	}
+
+	return Validate(id)
+}
+
+// Sequence extracts the sequence of the day from a valid consignment id
+func Sequence(id string) (uint64, error) {
+	if err := Validate(id); err != nil {
+		return 0, err
+	}
+
+	var seq uint64
+	for _, c := range []byte(strings.ToUpper(id[dateLength : Length-1])) {
+		seq = seq*base + uint64(digit(c))
+	}
+
+	return seq, nil
+}
+
+// checkDigit is the luhn mod 32 check character, it catches any single character typo
+// and most transpositions of adjacent characters
+func checkDigit(body string) int {
+	sum := 0
+	factor := 2
+
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * digit(body[i])
+		addend = addend/int(base) + addend%int(base)
+		sum += addend
+
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+	}
+
+	return (int(base) - sum%int(base)) % int(base)
+}
+
+func digit(c byte) int {
+	return strings.IndexByte(alphabet, c)
+}
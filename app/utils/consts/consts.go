@@ -1,13 +1,143 @@
 package consts
 
-import "next-oms/app/serializers"
-
 const (
 	AccessTokenType  = "access"
 	RefreshTokenType = "refresh"
 )
 
-const OrderPending = "Pending"
+const (
+	OrderPending   = "Pending"
+	OrderPicked    = "Picked"
+	OrderInTransit = "In Transit"
+	OrderDelivered = "Delivered"
+	OrderReturned  = "Returned"
+	OrderCancelled = "Cancelled"
+)
+
+const (
+	// BulkOrderMaxRows is the most rows a single bulk order request can carry
+	BulkOrderMaxRows = 1000
+	// BulkOrderBatchSize is how many orders of a bulk request are stored per transaction
+	BulkOrderBatchSize = 100
+)
+
+// built-in roles, the ids are fixed since the token user query matches admins by role id
+const (
+	RoleAdminID        uint = 1
+	RoleMerchantID     uint = 2
+	RoleSuperAdminID   uint = 3
+	RoleCompanyAdminID uint = 4
+)
+
+const (
+	PermissionOrderCancel        = "order.cancel"
+	PermissionOrderStatusUpdate  = "order.status.update"
+	PermissionPricingManage      = "pricing.manage"
+	PermissionLocationManage     = "location.manage"
+	PermissionRoleManage         = "role.manage"
+	PermissionCompanyManage      = "company.manage"
+	PermissionCompanyUsersManage = "company.users.manage"
+	PermissionAPIKeyManage       = "api_key.manage"
+	PermissionUserManage         = "user.manage"
+)
+
+// api key scopes, a key only reaches the routes of its scopes
+const (
+	ScopeOrdersRead    = "orders:read"
+	ScopeOrdersWrite   = "orders:write"
+	ScopeStoresRead    = "stores:read"
+	ScopeStoresWrite   = "stores:write"
+	ScopeLocationsRead = "locations:read"
+)
+
+const (
+	// APIKeyPrefix starts every api key, eg: oms_1a2b3c4d_<secret>
+	APIKeyPrefix = "oms"
+	// APIKeyPrefixLength is the length of the random hex part the keys are looked up by
+	APIKeyPrefixLength = 8
+	// APIKeySecretLength is the count of random bytes of the secret part
+	APIKeySecretLength = 32
+	// APIKeyUsedThrottle is the seconds between two last used updates of a key
+	APIKeyUsedThrottle = 60
+)
+
+// APIKeyScopeRoutes are the routes each scope allows, as "METHOD path" with the route path
+// the handlers are registered with
+var APIKeyScopeRoutes = map[string][]string{
+	ScopeOrdersRead: {
+		"GET /v1/orders/all",
+		"GET /v1/orders/:con_id",
+		"GET /v1/orders/by-merchant/:merchant_order_id",
+		"GET /v1/orders/:con_id/history",
+	},
+	ScopeOrdersWrite: {
+		"POST /v1/orders",
+		"POST /v1/orders/quote",
+		"POST /v1/orders/bulk",
+		"PUT /v1/orders/:con_id/cancel",
+		"PATCH /v1/orders/:con_id/status",
+	},
+	ScopeStoresRead: {
+		"GET /v1/stores",
+		"GET /v1/stores/:id",
+	},
+	ScopeStoresWrite: {
+		"POST /v1/stores",
+		"PUT /v1/stores/:id",
+		"DELETE /v1/stores/:id",
+	},
+	ScopeLocationsRead: {
+		"GET /v1/cities",
+		"GET /v1/cities/:id",
+		"GET /v1/cities/:id/zones",
+		"GET /v1/zones/:id",
+		"GET /v1/zones/:id/areas",
+		"GET /v1/areas/:id",
+	},
+}
+
+// RoleNames are the names the built-in roles are seeded with
+var RoleNames = map[uint]string{
+	RoleAdminID:        "admin",
+	RoleMerchantID:     "merchant",
+	RoleSuperAdminID:   "super_admin",
+	RoleCompanyAdminID: "company_admin",
+}
+
+// RolePermissions are the permissions the built-in roles are seeded with
+var RolePermissions = map[uint][]string{
+	RoleAdminID: {
+		PermissionOrderCancel,
+		PermissionOrderStatusUpdate,
+		PermissionPricingManage,
+		PermissionLocationManage,
+		PermissionRoleManage,
+		PermissionCompanyManage,
+		PermissionCompanyUsersManage,
+		PermissionAPIKeyManage,
+		PermissionUserManage,
+	},
+	RoleMerchantID: {
+		PermissionOrderCancel,
+		PermissionAPIKeyManage,
+	},
+	RoleCompanyAdminID: {
+		PermissionOrderCancel,
+		PermissionCompanyUsersManage,
+		PermissionAPIKeyManage,
+	},
+	RoleSuperAdminID: {
+		PermissionOrderCancel,
+		PermissionOrderStatusUpdate,
+		PermissionPricingManage,
+		PermissionLocationManage,
+		PermissionRoleManage,
+		PermissionCompanyManage,
+		PermissionCompanyUsersManage,
+		PermissionAPIKeyManage,
+		PermissionUserManage,
+	},
+}
 
 var ItemTypeMap = map[int]string{
 	1: "Electronics",
@@ -38,63 +168,3 @@ func GetOrderTypeDescription(orderTypeID int) string {
 	}
 	return "Unknown Order Type"
 }
-
-// OrderTypeDeliveryMap defines a mapping of order type IDs to discount fees (as percentages).
-var OrderTypeDeliveryMap = map[int]float64{
-	1: 0.0,  // Standard Delivery - No Discount
-	2: 5.0,  // Express Delivery - 5% Discount
-	3: 10.0, // Same-Day Delivery - 10% Discount
-	4: 15.0, // Scheduled Delivery - 15% Discount
-	5: 20.0, // Return Order - 20% Discount
-}
-
-func CalculateDeliveryFee(orderTypeID int, baseFee float64) float64 {
-	delivery, exists := OrderTypeDeliveryMap[orderTypeID]
-	if !exists {
-		delivery = 0.0
-	}
-	return baseFee - (baseFee * delivery / 100)
-}
-
-var itemTypeFees = map[int]float64{
-	1: 5.0,  // Regular item
-	2: 10.0, // Fragile item
-	3: 15.0, // Oversize item
-}
-
-const codFee = 5.0        // Flat COD fee
-const weightFeeRate = 2.0 // Fee per kg
-const zoneSurcharge = 3.0 // Flat zone surcharge for specific zones
-
-func CalculateTotalFee(order serializers.OrderReq) float64 {
-	// Base delivery fee
-	deliveryFee, exists := OrderTypeDeliveryMap[order.DeliveryType]
-	if !exists {
-		deliveryFee = 0 // Default to 0 if DeliveryType not recognized
-	}
-
-	// Item type fee
-	itemFee, exists := itemTypeFees[order.ItemType]
-	if !exists {
-		itemFee = 0 // Default to 0 if ItemType not recognized
-	}
-
-	// Weight-based fee
-	weightFee := order.ItemWeight * weightFeeRate
-
-	// COD Fee (if applicable)
-	codFeeApplied := 0.0
-	if order.AmountToCollect > 0 {
-		codFeeApplied = codFee
-	}
-
-	// Zone Surcharge (if applicable)
-	zoneFee := 0.0
-	if order.RecipientZone == 1 { // Example: zone 1 requires surcharge
-		zoneFee = zoneSurcharge
-	}
-
-	// Total fee calculation
-	totalFee := deliveryFee + itemFee + weightFee + codFeeApplied + zoneFee
-	return totalFee
-}
@@ -0,0 +1,64 @@
+package imageutil
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail crops the center square of the image & scales it to size x size. Every pixel of the
+// thumbnail is the average of the source pixels it covers, the transparent parts turn white
+func Thumbnail(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		sy0, sy1 := span(y, size, side)
+
+		for x := 0; x < size; x++ {
+			sx0, sx1 := span(x, size, side)
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(x0+sx, y0+sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					bl += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+
+			// the colors are alpha premultiplied, adding the missing alpha blends them over white
+			white := 0xffff - a/n
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/n + white) >> 8),
+				G: uint8((g/n + white) >> 8),
+				B: uint8((bl/n + white) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// span returns the source pixels the i-th of n thumbnail pixels covers, at least one so the
+// images smaller than the thumbnail are scaled up
+func span(i, n, side int) (int, int) {
+	from := i * side / n
+	to := (i + 1) * side / n
+	if to <= from {
+		to = from + 1
+	}
+
+	return from, to
+}
@@ -0,0 +1,147 @@
+package passwordutil
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"next-oms/infra/config"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// commonPasswords are the most used passwords of the public breach lists
+//
+//go:embed blocklist.txt
+var commonPasswords string
+
+var (
+	blocklist     map[string]bool
+	blocklistOnce sync.Once
+)
+
+// Check validates the password against the password policy & returns every rule it broke.
+// The personal values of the user, eg: the email, can't be part of the password
+func Check(password string, personal ...string) error {
+	conf := config.Password()
+	var violations []string
+
+	if length := len([]rune(password)); length < conf.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", conf.MinLength))
+	}
+
+	if conf.MaxLength > 0 && len(password) > conf.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", conf.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if conf.RequireUpper && !upper {
+		violations = append(violations, "must contain an upper case letter")
+	}
+
+	if conf.RequireLower && !lower {
+		violations = append(violations, "must contain a lower case letter")
+	}
+
+	if conf.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+
+	if conf.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if blocked(password) {
+		violations = append(violations, "is too common, it's on the list of breached passwords")
+	}
+
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.SplitN(value, "@", 2)[0])
+		if len(value) >= 4 && strings.Contains(lowered, value) {
+			violations = append(violations, "must not contain your name or email")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &errors.PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Hash hashes the password with the configured bcrypt cost
+func Hash(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), config.Password().BcryptCost)
+}
+
+// Expired reports whether a password changed at changedAt has to be changed
+func Expired(changedAt time.Time) bool {
+	days := config.Password().ExpiryDays
+	return days > 0 && time.Since(changedAt) > time.Duration(days)*24*time.Hour
+}
+
+// blocked looks the password up in the blocklist, the digits & symbols people add to the end of
+// a common password don't make it less common, eg: Password123!
+func blocked(password string) bool {
+	blocklistOnce.Do(loadBlocklist)
+
+	lowered := strings.ToLower(password)
+	if blocklist[lowered] {
+		return true
+	}
+
+	trimmed := strings.TrimRightFunc(lowered, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+
+	return trimmed != lowered && blocklist[trimmed]
+}
+
+func loadBlocklist() {
+	blocklist = map[string]bool{}
+	addBlocklist(bufio.NewScanner(strings.NewReader(commonPasswords)))
+
+	file := config.Password().BlocklistFile
+	if file == "" {
+		return
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		// the built-in list still applies, a missing file shouldn't stop signups
+		logger.Client().Error("failed to open password blocklist "+file, err)
+		return
+	}
+	defer f.Close()
+
+	addBlocklist(bufio.NewScanner(f))
+}
+
+func addBlocklist(scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			blocklist[strings.ToLower(line)] = true
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"next-oms/infra/config"
 	"next-oms/infra/conn/cache"
 	"next-oms/infra/conn/db"
+	"next-oms/infra/conn/mail"
+	"next-oms/infra/conn/storage"
+	"next-oms/infra/jwtkeys"
 	"next-oms/infra/logger"
 	"os"
 
@@ -27,8 +30,11 @@ func Execute() {
 	config.LoadConfig()
 	logger.NewLogClient(config.App().LogLevel)
 	lc := logger.Client()
+	jwtkeys.Load(lc)
 	db.NewDbClient(lc)
 	cache.NewCacheClient(lc)
+	mail.NewMailClient(lc)
+	storage.NewStorageClient(lc)
 
 	lc.Info("about to start the application")
 
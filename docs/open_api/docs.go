@@ -28,6 +28,7 @@
 package openapi
 
 import (
+	"next-oms/app/domain"
 	"next-oms/app/serializers"
 	"next-oms/infra/errors"
 )
@@ -88,3 +89,262 @@ type usersRespWrapper struct {
 	// in:body
 	Body serializers.ListFilters
 }
+
+// Sessions of the logged-in user
+// swagger:response SessionsResponse
+type sessionsRespWrapper struct {
+	// in:body
+	Body []serializers.SessionResp
+}
+
+// Id of a session
+// swagger:parameters RevokeSession
+type sessionIDParamWrapper struct {
+	// in:path
+	// required: true
+	ID string `json:"id"`
+}
+
+// First step of the login of a user with two factor
+// swagger:response TwoFactorChallengeResponse
+type twoFactorChallengeRespWrapper struct {
+	// in:body
+	Body serializers.TwoFactorChallengeResp
+}
+
+// Payload for finish the login of a user with two factor
+// swagger:parameters LoginTwoFactor
+type twoFactorLoginPayloadWrapper struct {
+	// in:body
+	Body serializers.TwoFactorLoginReq
+}
+
+// Payload for enroll a user with two factor on login
+// swagger:parameters EnrollTwoFactor
+type twoFactorLoginEnrollPayloadWrapper struct {
+	// in:body
+	Body serializers.TwoFactorLoginEnrollReq
+}
+
+// Secret of a two factor enrollment
+// swagger:response TwoFactorEnrollResponse
+type twoFactorEnrollRespWrapper struct {
+	// in:body
+	Body serializers.TwoFactorEnrollResp
+}
+
+// A totp code of the authenticator app
+// swagger:parameters ConfirmTwoFactor RegenerateBackupCodes
+type twoFactorCodePayloadWrapper struct {
+	// in:body
+	Body serializers.TwoFactorCodeReq
+}
+
+// Payload for turn two factor off
+// swagger:parameters DisableTwoFactor
+type twoFactorDisablePayloadWrapper struct {
+	// in:body
+	Body serializers.TwoFactorDisableReq
+}
+
+// Backup codes, shown only once
+// swagger:response BackupCodesResponse
+type backupCodesRespWrapper struct {
+	// in:body
+	Body serializers.BackupCodesResp
+}
+
+// Payload for create an api key
+// swagger:parameters CreateAPIKey
+type apiKeyPayloadWrapper struct {
+	// in:body
+	Body serializers.APIKeyReq
+}
+
+// The created api key, the key isn't shown again
+// swagger:response APIKeyCreatedResponse
+type apiKeyCreatedRespWrapper struct {
+	// in:body
+	Body serializers.APIKeyResp
+}
+
+// Api keys of the logged-in user
+// swagger:response APIKeysResponse
+type apiKeysRespWrapper struct {
+	// in:body
+	Body domain.APIKeys
+}
+
+// Id of an api key
+// swagger:parameters RevokeAPIKey
+type apiKeyIDParamWrapper struct {
+	// in:path
+	// required: true
+	ID uint `json:"id"`
+}
+
+// Consignment id of an order
+// swagger:parameters GetOrder GetOrderHistory
+type orderConsignmentIDParamWrapper struct {
+	// in:path
+	// required: true
+	ConID string `json:"con_id"`
+}
+
+// Merchant order id of an order
+// swagger:parameters GetOrderByMerchantOrderID
+type merchantOrderIDParamWrapper struct {
+	// in:path
+	// required: true
+	MerchantOrderID string `json:"merchant_order_id"`
+	// in:query
+	// required: true
+	StoreID int `json:"store_id"`
+}
+
+// A single order with its fee breakdown
+// swagger:response OrderResponse
+type orderRespWrapper struct {
+	// in:body
+	Body domain.Order
+}
+
+// Payload for change the status of an order
+// swagger:parameters UpdateOrderStatus
+type orderStatusPayloadWrapper struct {
+	// in:path
+	// required: true
+	ConID string `json:"con_id"`
+	// in:body
+	Body serializers.OrderStatusReq
+}
+
+// Status timeline of an order
+// swagger:response OrderHistoryResponse
+type orderHistoryRespWrapper struct {
+	// in:body
+	Body domain.OrderHistories
+}
+
+// Id of a rate card
+// swagger:parameters GetRateCard DeleteRateCard
+type rateCardIDParamWrapper struct {
+	// in:path
+	// required: true
+	ID uint `json:"id"`
+}
+
+// Payload for create a rate card
+// swagger:parameters CreateRateCard
+type rateCardPayloadWrapper struct {
+	// in:body
+	Body serializers.RateCardReq
+}
+
+// Payload for replace a rate card
+// swagger:parameters UpdateRateCard
+type rateCardUpdatePayloadWrapper struct {
+	// in:path
+	// required: true
+	ID uint `json:"id"`
+	// in:body
+	Body serializers.RateCardReq
+}
+
+// A rate card with all of its charges
+// swagger:response RateCardResponse
+type rateCardRespWrapper struct {
+	// in:body
+	Body domain.RateCard
+}
+
+// List all the rate cards
+// swagger:response RateCardsResponse
+type rateCardsRespWrapper struct {
+	// in:body
+	Body serializers.ListFilters
+}
+
+// Payload for create or quote an order
+// swagger:parameters CreateOrder QuoteOrder
+type orderPayloadWrapper struct {
+	// in:body
+	Body serializers.OrderReq
+}
+
+// Key to safely retry an order creation, the first response is replayed for the same key
+// swagger:parameters CreateOrder BulkCreateOrders
+type idempotencyKeyWrapper struct {
+	// in:header
+	// name: Idempotency-Key
+	IdempotencyKey string `json:"Idempotency-Key"`
+}
+
+// Fee breakdown of an order
+// swagger:response OrderQuoteResponse
+type orderQuoteRespWrapper struct {
+	// in:body
+	Body serializers.FeeBreakdown
+}
+
+// Payload for create orders in bulk
+// swagger:parameters BulkCreateOrders
+type bulkOrderPayloadWrapper struct {
+	// in:body
+	Body []serializers.OrderReq
+}
+
+// Per row report of a bulk order request
+// swagger:response BulkOrderResponse
+type bulkOrderRespWrapper struct {
+	// in:body
+	Body serializers.BulkOrderResp
+}
+
+// Payload for create a user as an admin
+// swagger:parameters AdminCreateUser
+type adminUserPayloadWrapper struct {
+	// in:body
+	Body serializers.AdminUserReq
+}
+
+// A single user
+// swagger:response AdminUserResponse
+type adminUserRespWrapper struct {
+	// in:body
+	Body serializers.UserResp
+}
+
+// Id of a user
+// swagger:parameters AdminGetUser AdminDeleteUser AdminRestoreUser AdminActivateUser AdminDeactivateUser AdminForcePasswordReset
+type adminUserIDParamWrapper struct {
+	// in:path
+	// required: true
+	ID uint `json:"id"`
+}
+
+// Payload for replace the role of a user
+// swagger:parameters AdminAssignRole
+type userRolePayloadWrapper struct {
+	// in:path
+	// required: true
+	ID uint `json:"id"`
+	// in:body
+	Body serializers.UserRoleReq
+}
+
+// Avatar image of the logged-in user
+// swagger:parameters UploadAvatar
+type avatarPayloadWrapper struct {
+	// in:formData
+	// swagger:file
+	// required: true
+	Avatar interface{} `json:"avatar"`
+}
+
+// The uploaded avatar & its thumbnails
+// swagger:response AvatarResponse
+type avatarRespWrapper struct {
+	// in:body
+	Body serializers.AvatarResp
+}
@@ -17,6 +17,9 @@ type AppConfig struct {
 	Sort            string
 	DefaultPageSize int64
 	LogLevel        string
+	// TrustedProxies are the cidr ranges of the proxies the client ip is taken from X-Forwarded-For
+	// behind, without any the ip of the connection is used
+	TrustedProxies []string
 }
 
 type DbClient struct {
@@ -28,10 +31,16 @@ type CacheClient struct {
 }
 
 type Config struct {
-	App   *AppConfig
-	Jwt   *JwtConfig
-	Db    DbClient
-	Cache CacheClient
+	App       *AppConfig
+	Jwt       *JwtConfig
+	Db        DbClient
+	Cache     CacheClient
+	Mail      *MailConfig
+	Login     *LoginConfig
+	TwoFactor *TwoFactorConfig
+	Password  *PasswordConfig
+	Storage   *StorageConfig
+	Avatar    *AvatarConfig
 }
 
 type DbConfig struct {
@@ -47,23 +56,109 @@ type DbConfig struct {
 }
 
 type JwtConfig struct {
-	AccessTokenSecret  string
-	RefreshTokenSecret string
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
-	ContextKey         string
+	SigningMethod        string            // HS256, RS256 or EdDSA, signing method of the access tokens
+	SigningKeyFile       string            // pem private key for RS256 & EdDSA
+	SigningKeyID         string            // kid of the signing key
+	VerificationKeyFiles map[string]string // kid: pem public key, keys of the rotated out signing keys
+	AccessTokenSecret    string
+	RefreshTokenSecret   string
+	AccessTokenExpiry    time.Duration
+	RefreshTokenExpiry   time.Duration
+	ResetTokenExpiry     time.Duration // minutes
+	InviteTokenExpiry    time.Duration // minutes
+	VerifyTokenSecret    string
+	VerifyTokenExpiry    time.Duration // minutes
+	ContextKey           string
 }
 
 type RedisConfig struct {
-	Host              string
-	Port              string
-	Pass              string
-	Db                int
-	AccessUuidPrefix  string
-	RefreshUuidPrefix string
-	UserPrefix        string
-	TokenPrefix       string
-	Ttl               int // seconds
+	Host               string
+	Port               string
+	Pass               string
+	Db                 int
+	AccessUuidPrefix   string
+	RefreshUuidPrefix  string
+	UserPrefix         string
+	TokenPrefix        string
+	Ttl                int // seconds
+	IdempotencyPrefix  string
+	IdempotencyTtl     int // seconds
+	ConsignmentPrefix  string
+	ResetTokenPrefix   string
+	VerifyResendPrefix string
+	VerifyResendTtl    int // seconds
+	LoginAttemptPrefix string
+	LoginLockPrefix    string
+	TokenFamilyPrefix  string
+	SessionPrefix      string
+	TwoFactorPrefix    string
+	TotpUsedPrefix     string
+}
+
+type MailConfig struct {
+	Transport        string // smtp, outbox or log
+	Host             string
+	Port             string
+	User             string
+	Pass             string
+	From             string
+	FromName         string
+	OutboxDir        string
+	QueueSize        int
+	Workers          int
+	MaxRetries       int
+	RetryInterval    int // seconds, doubled after every failed attempt
+	ResetPasswordUrl string
+	VerifyEmailUrl   string
+}
+
+type LoginConfig struct {
+	DelayAfter       int // failed attempts of an email before the responses are delayed
+	MaxDelay         int // seconds, the delay doubles with every failed attempt up to this
+	MaxEmailAttempts int // failed attempts of an email before it's locked
+	MaxIpAttempts    int // failed attempts from an ip before it's locked
+	AttemptWindow    int // seconds, failed attempts older than this are forgotten
+	LockDuration     int // seconds
+}
+
+type TwoFactorConfig struct {
+	Issuer          string // shown by the authenticator apps
+	ChallengeExpiry int    // seconds the challenge token of a login is valid
+	MaxAttempts     int    // wrong codes a challenge token takes before it's dropped
+	Skew            int    // time steps before & after the current one a code is accepted for
+	BackupCodes     int    // count of backup codes generated on enrollment
+}
+
+type PasswordConfig struct {
+	MinLength     int
+	MaxLength     int // bcrypt ignores everything after 72 bytes
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BlocklistFile string // optional, one password per line, on top of the built-in list
+	HistorySize   int    // last passwords which can't be used again, 0 turns the check off
+	ExpiryDays    int    // 0 never expires
+	BcryptCost    int
+}
+
+type StorageConfig struct {
+	Driver    string // local or s3
+	PublicURL string // the stored files are served under it, eg: http://localhost:8080/uploads
+	LocalDir  string
+	Endpoint  string // s3 compatible endpoint, eg: http://localhost:9000 for a local minio
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // bucket in the path instead of the host, minio needs it
+}
+
+type AvatarConfig struct {
+	MaxSize      int64 // bytes of the uploaded image
+	MaxDimension int   // pixels of the longer side, bigger images aren't decoded
+	Sizes        []int // pixels of the square thumbnails, the first one is the profile pic
+	Quality      int   // jpeg quality of the thumbnails
 }
 
 var config Config
@@ -84,6 +179,30 @@ func Cache() CacheClient {
 	return config.Cache
 }
 
+func Mail() *MailConfig {
+	return config.Mail
+}
+
+func Login() *LoginConfig {
+	return config.Login
+}
+
+func TwoFactor() *TwoFactorConfig {
+	return config.TwoFactor
+}
+
+func Password() *PasswordConfig {
+	return config.Password
+}
+
+func Storage() *StorageConfig {
+	return config.Storage
+}
+
+func Avatar() *AvatarConfig {
+	return config.Avatar
+}
+
 func LoadConfig() {
 	setDefaultConfig()
 
@@ -128,10 +247,15 @@ func setDefaultConfig() {
 	}
 
 	config.Jwt = &JwtConfig{
+		SigningMethod:      "HS256",
 		AccessTokenSecret:  "accesstokensecret",
 		RefreshTokenSecret: "refreshtokensecret",
 		AccessTokenExpiry:  300,
 		RefreshTokenExpiry: 10080,
+		ResetTokenExpiry:   30,
+		InviteTokenExpiry:  4320,
+		VerifyTokenSecret:  "verifytokensecret",
+		VerifyTokenExpiry:  1440,
 		ContextKey:         "user",
 	}
 
@@ -148,14 +272,85 @@ func setDefaultConfig() {
 	}
 
 	config.Cache.Redis = &RedisConfig{
-		Host:              "127.0.0.1",
-		Port:              "6390",
-		Pass:              "password123",
-		Db:                0,
-		AccessUuidPrefix:  "access-uuid_",
-		RefreshUuidPrefix: "refresh-uuid_",
-		UserPrefix:        "user_",
-		TokenPrefix:       "token_",
-		Ttl:               3600,
+		Host:               "127.0.0.1",
+		Port:               "6390",
+		Pass:               "password123",
+		Db:                 0,
+		AccessUuidPrefix:   "access-uuid_",
+		RefreshUuidPrefix:  "refresh-uuid_",
+		UserPrefix:         "user_",
+		TokenPrefix:        "token_",
+		Ttl:                3600,
+		IdempotencyPrefix:  "idempotency_",
+		IdempotencyTtl:     86400,
+		ConsignmentPrefix:  "consignment-seq_",
+		ResetTokenPrefix:   "reset-token_",
+		VerifyResendPrefix: "verify-resend_",
+		VerifyResendTtl:    60,
+		LoginAttemptPrefix: "login-attempt_",
+		LoginLockPrefix:    "login-lock_",
+		TokenFamilyPrefix:  "token-family_",
+		SessionPrefix:      "sessions_",
+		TwoFactorPrefix:    "2fa-challenge_",
+		TotpUsedPrefix:     "totp-used_",
+	}
+
+	config.Mail = &MailConfig{
+		Transport:        "outbox",
+		Host:             "127.0.0.1",
+		Port:             "1025",
+		From:             "no-reply@next-oms.local",
+		FromName:         "next-oms",
+		OutboxDir:        "outbox",
+		QueueSize:        100,
+		Workers:          2,
+		MaxRetries:       3,
+		RetryInterval:    5,
+		ResetPasswordUrl: "http://localhost:3000/reset-password",
+		VerifyEmailUrl:   "http://localhost:3000/verify-email",
+	}
+
+	config.Login = &LoginConfig{
+		DelayAfter:       3,
+		MaxDelay:         8,
+		MaxEmailAttempts: 10,
+		MaxIpAttempts:    50,
+		AttemptWindow:    900,
+		LockDuration:     900,
+	}
+
+	config.TwoFactor = &TwoFactorConfig{
+		Issuer:          "next-oms",
+		ChallengeExpiry: 300,
+		MaxAttempts:     5,
+		Skew:            1,
+		BackupCodes:     10,
+	}
+
+	config.Password = &PasswordConfig{
+		MinLength:     10,
+		MaxLength:     72,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: false,
+		HistorySize:   5,
+		ExpiryDays:    0,
+		BcryptCost:    12,
+	}
+
+	config.Storage = &StorageConfig{
+		Driver:    "local",
+		PublicURL: "http://localhost:8080/uploads",
+		LocalDir:  "uploads",
+		Region:    "us-east-1",
+		PathStyle: true,
+	}
+
+	config.Avatar = &AvatarConfig{
+		MaxSize:      5 << 20,
+		MaxDimension: 6000,
+		Sizes:        []int{256, 64},
+		Quality:      85,
 	}
 }
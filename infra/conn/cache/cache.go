@@ -22,6 +22,40 @@ func (cc CacheClient) Set(ctx context.Context, key string, value interface{}, tt
 	return cc.Redis.Set(ctx, key, string(serializedValue), time.Duration(ttl)*time.Second).Err()
 }
 
+// SetNX sets the key only if it doesn't exist yet, reports whether the key was set
+func (cc CacheClient) SetNX(ctx context.Context, key string, value interface{}, ttl int) (bool, error) {
+	if methodsutil.IsEmpty(key) || methodsutil.IsEmpty(value) {
+		return false, errors.ErrEmptyRedisKeyValue
+	}
+
+	serializedValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return cc.Redis.SetNX(ctx, key, string(serializedValue), time.Duration(ttl)*time.Second).Result()
+}
+
+// Incr increments the counter stored at key, the ttl is applied when the counter is created
+func (cc CacheClient) Incr(ctx context.Context, key string, ttl int) (int64, error) {
+	if methodsutil.IsEmpty(key) {
+		return 0, errors.ErrEmptyRedisKeyValue
+	}
+
+	val, err := cc.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if val == 1 {
+		if err := cc.Redis.Expire(ctx, key, time.Duration(ttl)*time.Second).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return val, nil
+}
+
 func (cc CacheClient) Get(ctx context.Context, key string) (string, error) {
 	if methodsutil.IsEmpty(key) {
 		return "", errors.ErrEmptyRedisKeyValue
@@ -64,6 +98,20 @@ func (cc CacheClient) Del(ctx context.Context, keys ...string) error {
 	return cc.Redis.Del(ctx, keys...).Err()
 }
 
+// Consume deletes the key and reports whether it was there, only one caller consumes a key
+func (cc CacheClient) Consume(ctx context.Context, key string) (bool, error) {
+	if methodsutil.IsEmpty(key) {
+		return false, errors.ErrEmptyRedisKeyValue
+	}
+
+	deleted, err := cc.Redis.Del(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
+
 func (cc CacheClient) DelPattern(ctx context.Context, pattern string) error {
 	iter := cc.Redis.Scan(ctx, 0, pattern, 0).Iterator()
 
@@ -80,3 +128,43 @@ func (cc CacheClient) DelPattern(ctx context.Context, pattern string) error {
 
 	return nil
 }
+
+// SAdd adds the members to the set stored at key, the ttl of the set is renewed
+func (cc CacheClient) SAdd(ctx context.Context, key string, ttl int, members ...string) error {
+	if methodsutil.IsEmpty(key) || len(members) == 0 {
+		return errors.ErrEmptyRedisKeyValue
+	}
+
+	args := make([]interface{}, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+
+	pipe := cc.Redis.TxPipeline()
+	pipe.SAdd(ctx, key, args...)
+	pipe.Expire(ctx, key, time.Duration(ttl)*time.Second)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func (cc CacheClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	if methodsutil.IsEmpty(key) {
+		return nil, errors.ErrEmptyRedisKeyValue
+	}
+
+	return cc.Redis.SMembers(ctx, key).Result()
+}
+
+func (cc CacheClient) SRem(ctx context.Context, key string, members ...string) error {
+	if methodsutil.IsEmpty(key) || len(members) == 0 {
+		return errors.ErrEmptyRedisKeyValue
+	}
+
+	args := make([]interface{}, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+
+	return cc.Redis.SRem(ctx, key, args...).Err()
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (dc DatabaseClient) SaveAPIKey(key *domain.APIKey) (*domain.APIKey, *errors.RestErr) {
+	mKey := &models.APIKey{
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    strings.Join(key.Scopes, ","),
+		UserID:    key.UserID,
+		CompanyID: key.CompanyID,
+		ExpiresAt: key.ExpiresAt,
+	}
+
+	res := dc.DB.Create(mKey)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create api key", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	key.ID = mKey.ID
+	key.CreatedAt = mKey.CreatedAt
+	key.UpdatedAt = mKey.UpdatedAt
+
+	return key, nil
+}
+
+// GetAPIKeysByUser lists the keys a user created, revoked keys included
+func (dc DatabaseClient) GetAPIKeysByUser(userID uint) (domain.APIKeys, *errors.RestErr) {
+	var mKeys []*models.APIKey
+
+	res := dc.DB.Where("user_id = ?", userID).Order("id DESC").Find(&mKeys)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting api keys of user", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	resp := domain.APIKeys{}
+	for _, mKey := range mKeys {
+		resp = append(resp, toDomainAPIKey(mKey))
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetAPIKeyByPrefix(prefix string) (*domain.APIKey, *errors.RestErr) {
+	var mKey models.APIKey
+
+	res := dc.DB.Where("prefix = ?", prefix).Limit(1).Find(&mKey)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting api key by prefix", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, errors.NewNotFoundError("api key not found")
+	}
+
+	return toDomainAPIKey(&mKey), nil
+}
+
+// RevokeAPIKey revokes a key of the user, revoking an already revoked key does nothing
+func (dc DatabaseClient) RevokeAPIKey(userID, id uint) *errors.RestErr {
+	var count int64
+
+	if err := dc.DB.Model(&models.APIKey{}).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+		dc.lc.Error("error occurred when getting api key by id", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if count == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("api key " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("api key not found")
+	}
+
+	res := dc.DB.Model(&models.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when revoking api key", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SetAPIKeyUsed(id uint, usedAt time.Time) error {
+	return dc.DB.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
+
+func toDomainAPIKey(mKey *models.APIKey) *domain.APIKey {
+	key := &domain.APIKey{
+		ID:         mKey.ID,
+		Name:       mKey.Name,
+		Prefix:     mKey.Prefix,
+		KeyHash:    mKey.KeyHash,
+		Scopes:     []string{},
+		UserID:     mKey.UserID,
+		CompanyID:  mKey.CompanyID,
+		ExpiresAt:  mKey.ExpiresAt,
+		LastUsedAt: mKey.LastUsedAt,
+		RevokedAt:  mKey.RevokedAt,
+		CreatedAt:  mKey.CreatedAt,
+		UpdatedAt:  mKey.UpdatedAt,
+	}
+
+	if mKey.Scopes != "" {
+		key.Scopes = strings.Split(mKey.Scopes, ",")
+	}
+
+	return key
+}
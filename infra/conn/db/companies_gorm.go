@@ -0,0 +1,245 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+	"time"
+)
+
+func (dc DatabaseClient) SaveBusiness(business *domain.Business) (*domain.Business, *errors.RestErr) {
+	mBusiness := &models.Business{
+		Name: business.Name,
+	}
+
+	res := dc.DB.Create(mBusiness)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create business", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	business.ID = mBusiness.ID
+	business.CreatedAt = mBusiness.CreatedAt
+	business.UpdatedAt = mBusiness.UpdatedAt
+
+	return business, nil
+}
+
+func (dc DatabaseClient) GetBusinesses(filters *serializers.ListFilters) (domain.Businesses, *errors.RestErr) {
+	var resp domain.Businesses
+
+	var totalRows int64 = 0
+	tableName := "businesses"
+	stmt := applyFilters(dc.DB.Model(&models.Business{}), tableName, filters, false)
+	countStmt := applyFilters(dc.DB.Model(&models.Business{}), tableName, filters, true)
+
+	res := stmt.Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting businesses", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total businesses count", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetBusinessByID(id uint) (*domain.Business, *errors.RestErr) {
+	var resp domain.Business
+
+	res := dc.DB.Model(&models.Business{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("business " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("business not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting business by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateBusiness(business *domain.Business) *errors.RestErr {
+	res := dc.DB.Model(&models.Business{}).Where("id = ?", business.ID).Update("name", business.Name)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating business by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// DeleteBusiness removes a business, businesses which still have companies are kept
+func (dc DatabaseClient) DeleteBusiness(id uint) *errors.RestErr {
+	var companies int64
+
+	if err := dc.DB.Model(&models.Company{}).Where("business_id = ?", id).Count(&companies).Error; err != nil {
+		dc.lc.Error("error occurred when counting companies of business", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if companies > 0 {
+		return errors.NewConflictError("business still has companies")
+	}
+
+	res := dc.DB.Where("id = ?", id).Delete(&models.Business{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting business by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("business " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("business not found")
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SaveCompany(company *domain.Company) (*domain.Company, *errors.RestErr) {
+	mCompany := &models.Company{
+		Name:       company.Name,
+		BusinessID: company.BusinessID,
+	}
+
+	res := dc.DB.Create(mCompany)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create company", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	company.ID = mCompany.ID
+	company.CreatedAt = mCompany.CreatedAt
+	company.UpdatedAt = mCompany.UpdatedAt
+
+	return company, nil
+}
+
+func (dc DatabaseClient) GetCompanies(filters *serializers.ListFilters) (domain.Companies, *errors.RestErr) {
+	var resp domain.Companies
+
+	var totalRows int64 = 0
+	tableName := "companies"
+	stmt := applyFilters(dc.DB.Model(&models.Company{}), tableName, filters, false)
+	countStmt := applyFilters(dc.DB.Model(&models.Company{}), tableName, filters, true)
+
+	res := stmt.Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting companies", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total companies count", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetCompanyByID(id uint) (*domain.Company, *errors.RestErr) {
+	var resp domain.Company
+
+	res := dc.DB.Model(&models.Company{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("company " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("company not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting company by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateCompany(company *domain.Company) *errors.RestErr {
+	res := dc.DB.Model(&models.Company{}).Where("id = ?", company.ID).Updates(map[string]interface{}{
+		"name":        company.Name,
+		"business_id": company.BusinessID,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating company by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// DeleteCompany removes a company, companies which still have users, stores, orders or live api
+// keys are kept so nothing is left scoped to a deleted company
+func (dc DatabaseClient) DeleteCompany(id uint) *errors.RestErr {
+	references := []struct {
+		name string
+		stmt *gorm.DB
+	}{
+		// soft deleted users & stores count as well, users can be restored & stores stay referenced by orders
+		{"users", dc.DB.Unscoped().Model(&models.User{}).Where("company_id = ?", id)},
+		{"stores", dc.DB.Unscoped().Model(&models.Store{}).Where("company_id = ?", id)},
+		{"orders", dc.DB.Model(&models.Order{}).Where("company_id = ?", id)},
+		{"api keys", dc.DB.Model(&models.APIKey{}).
+			Where("company_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", id, time.Now())},
+	}
+
+	for _, ref := range references {
+		var count int64
+
+		if err := ref.stmt.Count(&count).Error; err != nil {
+			dc.lc.Error("error occurred when counting "+ref.name+" of company", err)
+			return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		}
+
+		if count > 0 {
+			return errors.NewConflictError("company still has " + ref.name)
+		}
+	}
+
+	res := dc.DB.Where("id = ?", id).Delete(&models.Company{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting company by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("company " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("company not found")
+	}
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package db
 
 import (
+	goerrors "errors"
 	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"next-oms/app/domain"
 	"next-oms/app/serializers"
 	"next-oms/app/utils/methodsutil"
 	"strings"
@@ -84,3 +87,33 @@ func applyQueryStringSearch(stmt *gorm.DB, searchStmt, qs string) *gorm.DB {
 
 	return stmt
 }
+
+// applyTenantScope narrows a query of the table down to the tenant of the scope, rows outside of a
+// company belong to the user in the owner column
+func applyTenantScope(stmt *gorm.DB, tableName, ownerColumn string, scope *domain.TenantScope) *gorm.DB {
+	switch {
+	case scope.AllTenants:
+		return stmt
+	case scope.CompanyID != nil:
+		return stmt.Where(tableName+".company_id = ?", *scope.CompanyID)
+	default:
+		return stmt.Where(tableName+"."+ownerColumn+" = ?", scope.UserID)
+	}
+}
+
+const merchantOrderIndex = "idx_orders_store_merchant_order"
+
+// isDuplicateEntry reports whether err is a mysql violation of the named unique index
+func isDuplicateEntry(err error, index string) bool {
+	var mysqlErr *mysql.MySQLError
+	return goerrors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, index)
+}
+
+// nullableString stores empty strings as NULL, so optional columns don't clash on unique indexes
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -56,10 +56,60 @@ func connectMySQL(lc logger.LogClient) {
 	client.DB = dB
 	client.lc = lc
 
-	client.DB.AutoMigrate(
+	verifyExisting := !client.DB.Migrator().HasColumn(&models.User{}, "VerifiedAt")
+
+	if err := client.dedupeMerchantOrderIDs(); err != nil {
+		panic(err)
+	}
+
+	err = client.DB.AutoMigrate(
 		&models.Order{},
+		&models.OrderHistory{},
+		&models.Shipment{},
+		&models.RateCard{},
+		&models.RateCardDeliveryCharge{},
+		&models.RateCardItemCharge{},
+		&models.RateCardZoneCharge{},
+		&models.RateCardWeightBand{},
+		&models.City{},
+		&models.Zone{},
+		&models.Area{},
+		&models.Store{},
 		&models.User{},
+		&models.BackupCode{},
+		&models.PasswordHistory{},
+		&models.Role{},
+		&models.Permission{},
+		&models.RolePermission{},
+		&models.Business{},
+		&models.Company{},
+		&models.APIKey{},
 	)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := client.backfillStoreCompanies(); err != nil {
+		panic(err)
+	}
+
+	if err := client.backfillOrderOwners(); err != nil {
+		panic(err)
+	}
+
+	if err := client.backfillRateCardIDs(); err != nil {
+		panic(err)
+	}
+
+	if err := client.seedRoles(); err != nil {
+		panic(err)
+	}
+
+	if verifyExisting {
+		if err := client.verifyExistingUsers(); err != nil {
+			panic(err)
+		}
+	}
 
 	logger.Client().Info("mysql connection successful...")
 }
@@ -0,0 +1,278 @@
+package db
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+)
+
+func (dc DatabaseClient) SaveCity(city *domain.City) (*domain.City, *errors.RestErr) {
+	mCity := &models.City{
+		Name: city.Name,
+	}
+
+	res := dc.DB.Create(mCity)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create city", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	city.ID = mCity.ID
+	city.CreatedAt = mCity.CreatedAt
+	city.UpdatedAt = mCity.UpdatedAt
+
+	return city, nil
+}
+
+func (dc DatabaseClient) GetCities() (domain.Cities, *errors.RestErr) {
+	var resp domain.Cities
+
+	res := dc.DB.Model(&models.City{}).Order("name asc").Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting cities", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetCityByID(id uint) (*domain.City, *errors.RestErr) {
+	var resp domain.City
+
+	res := dc.DB.Model(&models.City{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("city " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("city not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting city by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateCity(city *domain.City) *errors.RestErr {
+	res := dc.DB.Model(&models.City{}).Where("id = ?", city.ID).Updates(map[string]interface{}{
+		"name": city.Name,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating city by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) DeleteCity(id uint) *errors.RestErr {
+	res := dc.DB.Where("id = ?", id).Delete(&models.City{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting city by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("city " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("city not found")
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SaveZone(zone *domain.Zone) (*domain.Zone, *errors.RestErr) {
+	mZone := &models.Zone{
+		CityID: zone.CityID,
+		Name:   zone.Name,
+	}
+
+	res := dc.DB.Create(mZone)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create zone", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	zone.ID = mZone.ID
+	zone.CreatedAt = mZone.CreatedAt
+	zone.UpdatedAt = mZone.UpdatedAt
+
+	return zone, nil
+}
+
+func (dc DatabaseClient) GetZones(cityID uint) (domain.Zones, *errors.RestErr) {
+	var resp domain.Zones
+
+	res := dc.DB.Model(&models.Zone{}).Where("city_id = ?", cityID).Order("name asc").Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting zones", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+// GetZonesByIDs returns the zones of the ids, unknown ids are left out
+func (dc DatabaseClient) GetZonesByIDs(ids []uint) (domain.Zones, *errors.RestErr) {
+	var resp domain.Zones
+
+	res := dc.DB.Model(&models.Zone{}).Where("id IN ?", ids).Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting zones by ids", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetZoneByID(id uint) (*domain.Zone, *errors.RestErr) {
+	var resp domain.Zone
+
+	res := dc.DB.Model(&models.Zone{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("zone " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("zone not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting zone by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateZone(zone *domain.Zone) *errors.RestErr {
+	res := dc.DB.Model(&models.Zone{}).Where("id = ?", zone.ID).Updates(map[string]interface{}{
+		"city_id": zone.CityID,
+		"name":    zone.Name,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating zone by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) DeleteZone(id uint) *errors.RestErr {
+	res := dc.DB.Where("id = ?", id).Delete(&models.Zone{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting zone by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("zone " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("zone not found")
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SaveArea(area *domain.Area) (*domain.Area, *errors.RestErr) {
+	mArea := &models.Area{
+		ZoneID: area.ZoneID,
+		Name:   area.Name,
+	}
+
+	res := dc.DB.Create(mArea)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create area", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	area.ID = mArea.ID
+	area.CreatedAt = mArea.CreatedAt
+	area.UpdatedAt = mArea.UpdatedAt
+
+	return area, nil
+}
+
+func (dc DatabaseClient) GetAreas(zoneID uint) (domain.Areas, *errors.RestErr) {
+	var resp domain.Areas
+
+	res := dc.DB.Model(&models.Area{}).Where("zone_id = ?", zoneID).Order("name asc").Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting areas", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+// GetAreasByIDs returns the areas of the ids, unknown ids are left out
+func (dc DatabaseClient) GetAreasByIDs(ids []uint) (domain.Areas, *errors.RestErr) {
+	var resp domain.Areas
+
+	res := dc.DB.Model(&models.Area{}).Where("id IN ?", ids).Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting areas by ids", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetAreaByID(id uint) (*domain.Area, *errors.RestErr) {
+	var resp domain.Area
+
+	res := dc.DB.Model(&models.Area{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("area " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("area not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting area by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateArea(area *domain.Area) *errors.RestErr {
+	res := dc.DB.Model(&models.Area{}).Where("id = ?", area.ID).Updates(map[string]interface{}{
+		"zone_id": area.ZoneID,
+		"name":    area.Name,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating area by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) DeleteArea(id uint) *errors.RestErr {
+	res := dc.DB.Where("id = ?", id).Delete(&models.Area{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting area by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("area " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("area not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type APIKey struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	Name       string `json:"name"`
+	Prefix     string `gorm:"uniqueIndex;size:16" json:"prefix"`
+	KeyHash    string `gorm:"size:64" json:"-"`
+	Scopes     string `json:"scopes"`
+	UserID     uint   `gorm:"index" json:"user_id"`
+	CompanyID  uint   `gorm:"index" json:"company_id"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Business struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type Company struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	Name       string `json:"name"`
+	BusinessID *uint  `gorm:"index" json:"business_id"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type City struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type Zone struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	CityID    uint   `gorm:"index" json:"city_id"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type Area struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	ZoneID    uint   `gorm:"index" json:"zone_id"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type OrderHistory struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	ConsignmentID string    `gorm:"index" json:"order_consignment_id"`
+	OldStatus     string    `json:"old_status"`
+	NewStatus     string    `json:"new_status"`
+	ActorID       uint      `json:"actor_id"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
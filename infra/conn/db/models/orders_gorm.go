@@ -4,9 +4,12 @@ import "time"
 
 type Order struct {
 	ID               uint    `gorm:"primarykey" json:"id"`
-	ConsignmentID    string  `json:"order_consignment_id"`
+	ConsignmentID    string  `gorm:"uniqueIndex;size:191" json:"order_consignment_id"`
 	Description      string  `json:"order_description"`
-	MerchantOrderID  string  `json:"merchant_order_id"`
+	CompanyID        *uint   `gorm:"index" json:"company_id"`
+	CreatedBy        uint    `gorm:"index" json:"created_by"`
+	StoreID          int     `gorm:"uniqueIndex:idx_orders_store_merchant_order" json:"store_id"`
+	MerchantOrderID  *string `gorm:"uniqueIndex:idx_orders_store_merchant_order;size:191" json:"merchant_order_id"`
 	RecipientName    string  `json:"recipient_name"`
 	RecipientAddress string  `json:"recipient_address"`
 	RecipientPhone   string  `json:"recipient_phone"`
@@ -18,6 +21,11 @@ type Order struct {
 	PromoDiscount    float64 `json:"promo_discount"`
 	Discount         float64 `json:"discount"`
 	DeliveryFee      float64 `json:"delivery_fee"`
+	ItemFee          float64 `json:"item_fee"`
+	WeightFee        float64 `json:"weight_fee"`
+	ZoneSurcharge    float64 `json:"zone_surcharge"`
+	RateCardID       uint    `gorm:"index" json:"rate_card_id"`
+	RateCardVersion  int     `json:"rate_card_version"`
 	Status           string  `json:"order_status"`
 	OrderType        string  `json:"order_type"`
 	ItemType         string  `json:"item_type"`
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// RateCard is a single version of a rate card, the versions of a card share the card id & are
+// never changed once a newer version supersedes them
+type RateCard struct {
+	ID              uint                     `gorm:"primarykey" json:"id"`
+	CardID          uint                     `gorm:"index" json:"card_id"`
+	Name            string                   `json:"name"`
+	Version         int                      `gorm:"default:1" json:"version"`
+	Active          bool                     `gorm:"index" json:"active"`
+	SupersededAt    *time.Time               `gorm:"index" json:"superseded_at"`
+	CodPercentage   float64                  `json:"cod_percentage"`
+	DeliveryCharges []RateCardDeliveryCharge `json:"delivery_charges"`
+	ItemCharges     []RateCardItemCharge     `json:"item_charges"`
+	ZoneCharges     []RateCardZoneCharge     `json:"zone_charges"`
+	WeightBands     []RateCardWeightBand     `json:"weight_bands"`
+	CreatedAt       time.Time                `json:"created_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt           `gorm:"index" json:"-"`
+}
+
+type RateCardDeliveryCharge struct {
+	ID           uint    `gorm:"primarykey" json:"-"`
+	RateCardID   uint    `gorm:"index" json:"-"`
+	DeliveryType int     `json:"delivery_type"`
+	Fee          float64 `json:"fee"`
+}
+
+type RateCardItemCharge struct {
+	ID         uint    `gorm:"primarykey" json:"-"`
+	RateCardID uint    `gorm:"index" json:"-"`
+	ItemType   int     `json:"item_type"`
+	Fee        float64 `json:"fee"`
+}
+
+type RateCardZoneCharge struct {
+	ID         uint    `gorm:"primarykey" json:"-"`
+	RateCardID uint    `gorm:"index" json:"-"`
+	ZoneID     int     `json:"zone_id"`
+	Surcharge  float64 `json:"surcharge"`
+}
+
+type RateCardWeightBand struct {
+	ID         uint    `gorm:"primarykey" json:"-"`
+	RateCardID uint    `gorm:"index" json:"-"`
+	MinWeight  float64 `json:"min_weight"`
+	MaxWeight  float64 `json:"max_weight"`
+	Fee        float64 `json:"fee"`
+}
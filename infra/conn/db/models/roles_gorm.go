@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type Role struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Name        string `gorm:"uniqueIndex;size:64" json:"name"`
+	Description string `json:"description"`
+	// RequireTwoFactor makes the holders enroll in two factor authentication on their next login
+	RequireTwoFactor bool `json:"require_two_factor"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type Permission struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Name        string `gorm:"uniqueIndex;size:128" json:"name"`
+	Description string `json:"description"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type RolePermission struct {
+	RoleID       uint `gorm:"primaryKey;autoIncrement:false" json:"role_id"`
+	PermissionID uint `gorm:"primaryKey;autoIncrement:false;index" json:"permission_id"`
+}
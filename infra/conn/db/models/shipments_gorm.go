@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Shipment struct {
+	ID            uint    `gorm:"primarykey" json:"id"`
+	ConsignmentID string  `gorm:"uniqueIndex;size:191" json:"order_consignment_id"`
+	StoreID       int     `json:"store_id"`
+	RecipientCity int     `json:"recipient_city"`
+	RecipientZone int     `json:"recipient_zone"`
+	RecipientArea int     `json:"recipient_area"`
+	DeliveryType  int     `json:"delivery_type"`
+	ItemType      int     `json:"item_type"`
+	ItemQuantity  int     `json:"item_quantity"`
+	ItemWeight    float64 `json:"item_weight"`
+	ItemLength    float64 `json:"item_length"`
+	ItemWidth     float64 `json:"item_width"`
+	ItemHeight    float64 `json:"item_height"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
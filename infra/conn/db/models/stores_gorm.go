@@ -0,0 +1,21 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Store struct {
+	ID           uint   `gorm:"primarykey" json:"id"`
+	UserID       uint   `gorm:"index" json:"user_id"`
+	CompanyID    *uint  `gorm:"index" json:"company_id"`
+	Name         string `json:"name"`
+	ContactPhone string `json:"contact_phone"`
+	Address      string `json:"address"`
+	CityID       uint   `json:"city_id"`
+	ZoneID       uint   `json:"zone_id"`
+	AreaID       uint   `json:"area_id"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
@@ -14,9 +14,33 @@ type User struct {
 	Password    *string    `json:"password,omitempty"`
 	Phone       string     `json:"phone"`
 	ProfilePic  *string    `json:"profile_pic"`
-	LastLoginAt *time.Time `json:"last_login_at"`
-	FirstLogin  bool       `json:"first_login" gorm:"column:first_login;default:true"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	CompanyID   *uint      `gorm:"index" json:"company_id"`
+	RoleID      uint       `gorm:"index" json:"role_id"`
+	Active      bool       `gorm:"default:true" json:"active"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	TotpSecret  *string    `json:"-"`
+	TwoFactorAt *time.Time `json:"two_factor_at"`
+	// PasswordChangedAt is nil for the users created before password expiry existed
+	PasswordChangedAt *time.Time `json:"password_changed_at"`
+	LastLoginAt       *time.Time `json:"last_login_at"`
+	FirstLogin        bool       `json:"first_login" gorm:"column:first_login;default:true"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+}
+
+// BackupCode is a single use code that stands in for a totp code, only its hash is stored
+type BackupCode struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	UserID   uint   `gorm:"index" json:"user_id"`
+	CodeHash string `gorm:"size:64" json:"-"`
+	UsedAt   *time.Time
+}
+
+// PasswordHistory keeps the hashes of the last passwords of a user so they aren't used again
+type PasswordHistory struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	UserID    uint   `gorm:"index" json:"user_id"`
+	Password  string `json:"-"`
+	CreatedAt time.Time
 }
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"next-oms/app/domain"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+)
+
+func (dc DatabaseClient) GetOrderHistory(conID string) (domain.OrderHistories, *errors.RestErr) {
+	var resp domain.OrderHistories
+
+	res := dc.DB.Model(&models.OrderHistory{}).
+		Where("consignment_id = ?", conID).
+		Order("created_at asc, id asc").
+		Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting order history", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+// createOrderHistory records a status change, it runs on the caller's transaction
+func createOrderHistory(tx *gorm.DB, history *domain.OrderHistory) error {
+	return tx.Create(&models.OrderHistory{
+		ConsignmentID: history.ConsignmentID,
+		OldStatus:     history.OldStatus,
+		NewStatus:     history.NewStatus,
+		ActorID:       history.ActorID,
+		Reason:        history.Reason,
+	}).Error
+}
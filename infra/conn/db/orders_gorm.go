@@ -1,6 +1,8 @@
 package db
 
 import (
+	"fmt"
+	"gorm.io/gorm"
 	"next-oms/app/domain"
 	"next-oms/app/serializers"
 	"next-oms/app/utils/msgutil"
@@ -8,44 +10,56 @@ import (
 	"next-oms/infra/errors"
 )
 
-func (dc DatabaseClient) SaveOrder(order *domain.Order) (*domain.Order, *errors.RestErr) {
-	mOrder := &models.Order{
-		ConsignmentID:    order.ConsignmentID,
-		Description:      order.Description,
-		MerchantOrderID:  order.MerchantOrderID,
-		RecipientName:    order.RecipientName,
-		RecipientAddress: order.RecipientAddress,
-		RecipientPhone:   order.RecipientPhone,
-		Amount:           order.Amount,
-		TotalFee:         order.TotalFee,
-		Instruction:      order.Instruction,
-		OrderTypeID:      order.OrderTypeID,
-		CodFee:           order.CodFee,
-		PromoDiscount:    order.PromoDiscount,
-		Discount:         order.Discount,
-		DeliveryFee:      order.DeliveryFee,
-		Status:           order.Status,
-		OrderType:        order.OrderType,
-		ItemType:         order.ItemType,
-	}
+func (dc DatabaseClient) SaveOrder(order *domain.Order, actorID uint) (*domain.Order, *errors.RestErr) {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		return createOrder(tx, order, actorID)
+	})
 
-	res := dc.DB.Model(&models.Order{}).Create(&mOrder)
+	if isDuplicateEntry(err, merchantOrderIndex) {
+		dc.lc.Warn(fmt.Sprintf("merchant order %s of store %d already exists", order.MerchantOrderID, order.StoreID))
+		return nil, errors.NewConflictError("merchant_order_id already exists for the store")
+	}
 
-	if res.Error != nil {
-		dc.lc.Error("error occurred when create order", res.Error)
+	if err != nil {
+		dc.lc.Error("error occurred when create order", err)
 		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
 	return order, nil
 }
 
-func (dc DatabaseClient) GetOrders(filters *serializers.ListFilters) (domain.Orders, *errors.RestErr) {
+// SaveOrders stores a batch of orders in a single transaction, either all of them are stored or none
+func (dc DatabaseClient) SaveOrders(orders domain.Orders, actorID uint) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		for _, order := range orders {
+			if err := createOrder(tx, order, actorID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if isDuplicateEntry(err, merchantOrderIndex) {
+		dc.lc.Warn("a merchant order of the batch already exists")
+		return errors.NewConflictError("merchant_order_id already exists for the store")
+	}
+
+	if err != nil {
+		dc.lc.Error("error occurred when create orders in bulk", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) GetOrders(scope *domain.TenantScope, filters *serializers.ListFilters) (domain.Orders, *errors.RestErr) {
 	var resp domain.Orders
 
 	var totalRows int64 = 0
 	tableName := "orders"
-	stmt := applyFilters(dc.DB, tableName, filters, false)
-	countStmt := applyFilters(dc.DB, tableName, filters, true)
+	stmt := applyFilters(applyTenantScope(dc.DB, "orders", "created_by", scope), tableName, filters, false)
+	countStmt := applyFilters(applyTenantScope(dc.DB, "orders", "created_by", scope), tableName, filters, true)
 
 	res := stmt.Find(&resp)
 
@@ -70,20 +84,200 @@ func (dc DatabaseClient) GetOrders(filters *serializers.ListFilters) (domain.Ord
 	return resp, nil
 }
 
-func (dc DatabaseClient) CancelOrder(conID string) *errors.RestErr {
-	res := dc.DB.Model(&models.Order{}).
-		Where("consignment_id = ?", conID).
-		Update("status", "Cancelled")
+func (dc DatabaseClient) GetOrderByConsignmentID(scope *domain.TenantScope, conID string) (*domain.Order, *errors.RestErr) {
+	var resp domain.Order
+
+	res := applyTenantScope(dc.DB, "orders", "created_by", scope).Model(&models.Order{}).Where("consignment_id = ?", conID).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg(conID))
+		return nil, errors.NewNotFoundError("order not found")
+	}
 
 	if res.Error != nil {
-		dc.lc.Error(msgutil.EntityGenericFailedMsg("cancel order"), res.Error)
-		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+		dc.lc.Error("error occurred when getting order by consignment id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
+	return &resp, nil
+}
+
+// GetOrderByMerchantOrderID returns the order of the store with the merchant order id, the id is
+// only unique within a store
+func (dc DatabaseClient) GetOrderByMerchantOrderID(scope *domain.TenantScope, storeID int, merchantOrderID string) (*domain.Order, *errors.RestErr) {
+	var resp domain.Order
+
+	res := applyTenantScope(dc.DB, "orders", "created_by", scope).Model(&models.Order{}).
+		Where("store_id = ? AND merchant_order_id = ?", storeID, merchantOrderID).
+		First(&resp)
+
 	if res.RowsAffected == 0 {
-		dc.lc.Warn(msgutil.EntityNotFoundMsg(conID))
-		return errors.NewNotFoundError("order not found")
+		dc.lc.Warn(msgutil.EntityNotFoundMsg(merchantOrderID))
+		return nil, errors.NewNotFoundError("order not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting order by merchant order id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+// GetMerchantOrders returns the store & merchant order id of the stored orders matching any of the
+// stores & any of the merchant order ids
+func (dc DatabaseClient) GetMerchantOrders(storeIDs []int, merchantOrderIDs []string) (domain.Orders, *errors.RestErr) {
+	var resp domain.Orders
+
+	res := dc.DB.Model(&models.Order{}).
+		Select("store_id", "merchant_order_id").
+		Where("store_id IN ? AND merchant_order_id IN ?", storeIDs, merchantOrderIDs).
+		Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when checking merchant order ids", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+// GetLastConsignmentID returns the greatest consignment id starting with prefix, empty if there's none
+func (dc DatabaseClient) GetLastConsignmentID(prefix string) (string, *errors.RestErr) {
+	var conIDs []string
+
+	res := dc.DB.Model(&models.Order{}).
+		Where("consignment_id LIKE ?", prefix+"%").
+		Order("consignment_id DESC").
+		Limit(1).
+		Pluck("consignment_id", &conIDs)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting last consignment id", res.Error)
+		return "", errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if len(conIDs) == 0 {
+		return "", nil
+	}
+
+	return conIDs[0], nil
+}
+
+func (dc DatabaseClient) UpdateOrderStatus(history *domain.OrderHistory) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Order{}).
+			Where("consignment_id = ? AND status = ?", history.ConsignmentID, history.OldStatus).
+			Update("status", history.NewStatus)
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		// the status is matched as well, so a concurrent update makes this a no-op
+		if res.RowsAffected == 0 {
+			return errors.ErrOrderStatusChanged
+		}
+
+		return createOrderHistory(tx, history)
+	})
+
+	if err == errors.ErrOrderStatusChanged {
+		dc.lc.Warn(fmt.Sprintf("order %s is no longer in %s status", history.ConsignmentID, history.OldStatus))
+		return errors.NewConflictError("order status has been changed by another request")
+	}
+
+	if err != nil {
+		dc.lc.Error(msgutil.EntityGenericFailedMsg("update order status"), err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
 	return nil
 }
+
+// createOrder stores an order along with its shipment and first history entry,
+// it runs on the caller's transaction
+func createOrder(tx *gorm.DB, order *domain.Order, actorID uint) error {
+	mOrder := &models.Order{
+		ConsignmentID:    order.ConsignmentID,
+		Description:      order.Description,
+		CompanyID:        order.CompanyID,
+		CreatedBy:        order.CreatedBy,
+		StoreID:          order.StoreID,
+		MerchantOrderID:  nullableString(order.MerchantOrderID),
+		RecipientName:    order.RecipientName,
+		RecipientAddress: order.RecipientAddress,
+		RecipientPhone:   order.RecipientPhone,
+		Amount:           order.Amount,
+		TotalFee:         order.TotalFee,
+		Instruction:      order.Instruction,
+		OrderTypeID:      order.OrderTypeID,
+		CodFee:           order.CodFee,
+		PromoDiscount:    order.PromoDiscount,
+		Discount:         order.Discount,
+		DeliveryFee:      order.DeliveryFee,
+		ItemFee:          order.ItemFee,
+		WeightFee:        order.WeightFee,
+		ZoneSurcharge:    order.ZoneSurcharge,
+		RateCardID:       order.RateCardID,
+		RateCardVersion:  order.RateCardVersion,
+		Status:           order.Status,
+		OrderType:        order.OrderType,
+		ItemType:         order.ItemType,
+	}
+
+	if err := tx.Model(&models.Order{}).Create(&mOrder).Error; err != nil {
+		return err
+	}
+
+	if order.Shipment != nil {
+		if err := createShipment(tx, order.Shipment); err != nil {
+			return err
+		}
+	}
+
+	return createOrderHistory(tx, &domain.OrderHistory{
+		ConsignmentID: order.ConsignmentID,
+		NewStatus:     order.Status,
+		ActorID:       actorID,
+		Reason:        "order created",
+	})
+}
+
+// dedupeMerchantOrderIDs prepares the orders created before merchant_order_id was unique per store
+// for its index, empty ids become NULL & of every duplicate only the first order keeps its id
+func (dc DatabaseClient) dedupeMerchantOrderIDs() error {
+	migrator := dc.DB.Migrator()
+	if !migrator.HasColumn(&models.Order{}, "MerchantOrderID") || migrator.HasIndex(&models.Order{}, merchantOrderIndex) {
+		return nil
+	}
+
+	return dc.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Order{}).Where("merchant_order_id = ''").Update("merchant_order_id", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return tx.Exec(`UPDATE orders
+			JOIN (
+				SELECT store_id, merchant_order_id, MIN(id) AS first_id
+				FROM orders
+				WHERE merchant_order_id IS NOT NULL
+				GROUP BY store_id, merchant_order_id
+				HAVING COUNT(*) > 1
+			) AS duplicates
+			ON orders.store_id = duplicates.store_id AND orders.merchant_order_id = duplicates.merchant_order_id
+			SET orders.merchant_order_id = NULL
+			WHERE orders.id <> duplicates.first_id`).Error
+	})
+}
+
+// backfillOrderOwners gives the orders created before they were scoped to a tenant the owner of
+// their store as the creator & that owner's company, orders of unknown stores are left to admins
+func (dc DatabaseClient) backfillOrderOwners() error {
+	return dc.DB.Exec(`UPDATE orders
+		JOIN stores ON stores.id = orders.store_id
+		LEFT JOIN users ON users.id = stores.user_id
+		SET orders.created_by = stores.user_id, orders.company_id = users.company_id
+		WHERE orders.created_by = 0 AND orders.company_id IS NULL`).Error
+}
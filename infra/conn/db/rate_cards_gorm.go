@@ -0,0 +1,234 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/app/utils/methodsutil"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+	"time"
+)
+
+func (dc DatabaseClient) SaveRateCard(card *domain.RateCard) (*domain.RateCard, *errors.RestErr) {
+	mCard := &models.RateCard{}
+
+	if err := methodsutil.StructToStruct(card, mCard); err != nil {
+		dc.lc.Error(msgutil.EntityStructToStructFailedMsg("rate card to model"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	mCard.Version = 1
+
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		if mCard.Active {
+			if err := deactivateRateCards(tx); err != nil {
+				return err
+			}
+		}
+
+		// charges are has-many associations, so they are created along with the card
+		if err := tx.Create(mCard).Error; err != nil {
+			return err
+		}
+
+		// the first version names the card
+		return tx.Model(mCard).Update("card_id", mCard.ID).Error
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when create rate card", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return dc.GetRateCardByID(mCard.ID)
+}
+
+func (dc DatabaseClient) GetRateCards(filters *serializers.ListFilters) (domain.RateCards, *errors.RestErr) {
+	var mCards []models.RateCard
+	var resp domain.RateCards
+
+	var totalRows int64 = 0
+	tableName := "rate_cards"
+	// only the current version of every card is listed, the older ones are reached by id
+	stmt := applyFilters(rateCardQuery(dc.DB).Where("superseded_at IS NULL"), tableName, filters, false)
+	countStmt := applyFilters(dc.DB.Where("superseded_at IS NULL"), tableName, filters, true)
+
+	res := stmt.Find(&mCards)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting rate cards", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if err := methodsutil.StructToStruct(mCards, &resp); err != nil {
+		dc.lc.Error(msgutil.EntityStructToStructFailedMsg("rate cards"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Model(&models.RateCard{}).Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total rate cards count", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetRateCardByID(id uint) (*domain.RateCard, *errors.RestErr) {
+	return dc.getRateCard(rateCardQuery(dc.DB).Where("id = ?", id), strconv.Itoa(int(id)))
+}
+
+func (dc DatabaseClient) GetActiveRateCard() (*domain.RateCard, *errors.RestErr) {
+	return dc.getRateCard(rateCardQuery(dc.DB).Where("active = ?", true).Order("updated_at desc"), "active rate card")
+}
+
+// UpdateRateCard adds a new version of the card the given version belongs to, the current version
+// is superseded but keeps its charges since orders were priced with it
+func (dc DatabaseClient) UpdateRateCard(card *domain.RateCard) (*domain.RateCard, *errors.RestErr) {
+	mCard := &models.RateCard{}
+
+	if err := methodsutil.StructToStruct(card, mCard); err != nil {
+		dc.lc.Error(msgutil.EntityStructToStructFailedMsg("rate card to model"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := currentRateCard(tx, card.ID)
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.RateCard{}).Where("id = ?", current.ID).Updates(map[string]interface{}{
+			"active":        false,
+			"superseded_at": time.Now(),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if mCard.Active {
+			if err := deactivateRateCards(tx); err != nil {
+				return err
+			}
+		}
+
+		mCard.ID = 0
+		mCard.CardID = current.CardID
+		mCard.Version = current.Version + 1
+
+		return tx.Create(mCard).Error
+	})
+
+	if err == gorm.ErrRecordNotFound {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("rate card " + strconv.Itoa(int(card.ID))))
+		return nil, errors.NewNotFoundError("rate card not found")
+	}
+
+	if err != nil {
+		dc.lc.Error("error occurred when update rate card", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return dc.GetRateCardByID(mCard.ID)
+}
+
+// DeleteRateCard deletes every version of the card the given version belongs to, the versions are
+// soft deleted so the charges which priced the orders are kept
+func (dc DatabaseClient) DeleteRateCard(id uint) *errors.RestErr {
+	var cardID uint
+
+	res := dc.DB.Model(&models.RateCard{}).Where("id = ?", id).Limit(1).Pluck("card_id", &cardID)
+	if res.Error != nil {
+		dc.lc.Error("error occurred when delete rate card", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("rate card " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("rate card not found")
+	}
+
+	if err := dc.DB.Where("card_id = ?", cardID).Delete(&models.RateCard{}).Error; err != nil {
+		dc.lc.Error("error occurred when delete rate card", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) getRateCard(stmt *gorm.DB, identifier string) (*domain.RateCard, *errors.RestErr) {
+	var mCard models.RateCard
+	var resp domain.RateCard
+
+	res := stmt.First(&mCard)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg(identifier))
+		return nil, errors.NewNotFoundError("rate card not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting rate card", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if err := methodsutil.StructToStruct(mCard, &resp); err != nil {
+		dc.lc.Error(msgutil.EntityStructToStructFailedMsg("rate card"), err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func rateCardQuery(stmt *gorm.DB) *gorm.DB {
+	return stmt.Model(&models.RateCard{}).
+		Preload("DeliveryCharges").
+		Preload("ItemCharges").
+		Preload("ZoneCharges").
+		Preload("WeightBands")
+}
+
+// deactivateRateCards makes room for a new active card, only one card prices the orders
+func deactivateRateCards(tx *gorm.DB) error {
+	return tx.Model(&models.RateCard{}).Where("active = ?", true).Update("active", false).Error
+}
+
+// currentRateCard locks & returns the current version of the card the given version belongs to
+func currentRateCard(tx *gorm.DB, id uint) (*models.RateCard, error) {
+	var version models.RateCard
+	if err := tx.Where("id = ?", id).First(&version).Error; err != nil {
+		return nil, err
+	}
+
+	var current models.RateCard
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("card_id = ? AND superseded_at IS NULL", version.CardID).
+		First(&current).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &current, nil
+}
+
+// backfillRateCardIDs makes the cards created before the versions had their own rows the first
+// version of themselves
+func (dc DatabaseClient) backfillRateCardIDs() error {
+	return dc.DB.Model(&models.RateCard{}).
+		Unscoped().
+		Where("card_id = 0 OR card_id IS NULL").
+		Update("card_id", gorm.Expr("id")).
+		Error
+}
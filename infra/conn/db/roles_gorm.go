@@ -0,0 +1,340 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"next-oms/app/domain"
+	"next-oms/app/utils/consts"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+)
+
+const (
+	roleNameIndex       = "idx_roles_name"
+	permissionNameIndex = "idx_permissions_name"
+)
+
+func (dc DatabaseClient) SaveRole(role *domain.Role) (*domain.Role, *errors.RestErr) {
+	mRole := &models.Role{
+		Name:             role.Name,
+		Description:      role.Description,
+		RequireTwoFactor: role.RequireTwoFactor,
+	}
+
+	res := dc.DB.Create(mRole)
+
+	if isDuplicateEntry(res.Error, roleNameIndex) {
+		dc.lc.Warn("role " + role.Name + " already exists")
+		return nil, errors.NewConflictError("role name already exists")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create role", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	role.ID = mRole.ID
+	role.CreatedAt = mRole.CreatedAt
+	role.UpdatedAt = mRole.UpdatedAt
+
+	return role, nil
+}
+
+func (dc DatabaseClient) GetRoles() (domain.Roles, *errors.RestErr) {
+	var resp domain.Roles
+
+	res := dc.DB.Model(&models.Role{}).Order("id").Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting roles", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetRoleByID(id uint) (*domain.Role, *errors.RestErr) {
+	var resp domain.Role
+
+	res := dc.DB.Model(&models.Role{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("role " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("role not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting role by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	err := dc.DB.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", id).
+		Order("permissions.name").
+		Find(&resp.Permissions).Error
+
+	if err != nil {
+		dc.lc.Error("error occurred when getting permissions of role", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) UpdateRole(role *domain.Role) *errors.RestErr {
+	res := dc.DB.Model(&models.Role{}).Where("id = ?", role.ID).Updates(map[string]interface{}{
+		"name":               role.Name,
+		"description":        role.Description,
+		"require_two_factor": role.RequireTwoFactor,
+	})
+
+	if isDuplicateEntry(res.Error, roleNameIndex) {
+		dc.lc.Warn("role " + role.Name + " already exists")
+		return errors.NewConflictError("role name already exists")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating role by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// DeleteRole removes a role along with its permissions, roles still held by users are kept
+func (dc DatabaseClient) DeleteRole(id uint) *errors.RestErr {
+	var holders int64
+
+	if err := dc.DB.Model(&models.User{}).Where("role_id = ?", id).Count(&holders).Error; err != nil {
+		dc.lc.Error("error occurred when counting users of role", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if holders > 0 {
+		return errors.NewConflictError("role is assigned to users")
+	}
+
+	var affected int64
+
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("id = ?", id).Delete(&models.Role{})
+		affected = res.RowsAffected
+
+		return res.Error
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when deleting role by id", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if affected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("role " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("role not found")
+	}
+
+	return nil
+}
+
+// SetRolePermissions replaces the permissions of a role with the given ones
+func (dc DatabaseClient) SetRolePermissions(roleID uint, permissionIDs []uint) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+
+		var rolePermissions []*models.RolePermission
+		for _, permissionID := range permissionIDs {
+			rolePermissions = append(rolePermissions, &models.RolePermission{
+				RoleID:       roleID,
+				PermissionID: permissionID,
+			})
+		}
+
+		return tx.Create(&rolePermissions).Error
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when setting role permissions", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SavePermission(permission *domain.Permission) (*domain.Permission, *errors.RestErr) {
+	mPermission := &models.Permission{
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+
+	res := dc.DB.Create(mPermission)
+
+	if isDuplicateEntry(res.Error, permissionNameIndex) {
+		dc.lc.Warn("permission " + permission.Name + " already exists")
+		return nil, errors.NewConflictError("permission name already exists")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create permission", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	permission.ID = mPermission.ID
+	permission.CreatedAt = mPermission.CreatedAt
+	permission.UpdatedAt = mPermission.UpdatedAt
+
+	return permission, nil
+}
+
+func (dc DatabaseClient) GetPermissions() (domain.Permissions, *errors.RestErr) {
+	var resp domain.Permissions
+
+	res := dc.DB.Model(&models.Permission{}).Order("name").Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting permissions", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) GetPermissionByID(id uint) (*domain.Permission, *errors.RestErr) {
+	var resp domain.Permission
+
+	res := dc.DB.Model(&models.Permission{}).Where("id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("permission " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("permission not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting permission by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+func (dc DatabaseClient) GetPermissionsByIDs(ids []uint) (domain.Permissions, *errors.RestErr) {
+	var resp domain.Permissions
+
+	res := dc.DB.Model(&models.Permission{}).Where("id IN ?", ids).Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting permissions by ids", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) UpdatePermission(permission *domain.Permission) *errors.RestErr {
+	res := dc.DB.Model(&models.Permission{}).Where("id = ?", permission.ID).Updates(map[string]interface{}{
+		"name":        permission.Name,
+		"description": permission.Description,
+	})
+
+	if isDuplicateEntry(res.Error, permissionNameIndex) {
+		dc.lc.Warn("permission " + permission.Name + " already exists")
+		return errors.NewConflictError("permission name already exists")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating permission by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// DeletePermission removes a permission and takes it away from every role
+func (dc DatabaseClient) DeletePermission(id uint) *errors.RestErr {
+	var affected int64
+
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("permission_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("id = ?", id).Delete(&models.Permission{})
+		affected = res.RowsAffected
+
+		return res.Error
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when deleting permission by id", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if affected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("permission " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("permission not found")
+	}
+
+	return nil
+}
+
+// seedRoles creates the built-in roles with their permissions and gives the merchant role
+// to users created before roles existed. It runs on every start, a built-in role only gets
+// the permissions that are new to the database so changes made through the api are kept
+func (dc DatabaseClient) seedRoles() error {
+	return dc.DB.Transaction(func(tx *gorm.DB) error {
+		permissionIDs := map[string]uint{}
+		newPermissions := map[string]bool{}
+
+		for _, names := range consts.RolePermissions {
+			for _, name := range names {
+				if _, ok := permissionIDs[name]; ok {
+					continue
+				}
+
+				permission := models.Permission{Name: name}
+				res := tx.Where(models.Permission{Name: name}).FirstOrCreate(&permission)
+				if res.Error != nil {
+					return res.Error
+				}
+
+				permissionIDs[name] = permission.ID
+				newPermissions[name] = res.RowsAffected == 1
+			}
+		}
+
+		for id, name := range consts.RoleNames {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Role{ID: id, Name: name})
+			if res.Error != nil {
+				return res.Error
+			}
+
+			roleCreated := res.RowsAffected == 1
+
+			for _, name := range consts.RolePermissions[id] {
+				if !roleCreated && !newPermissions[name] {
+					continue
+				}
+
+				err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+					Create(&models.RolePermission{RoleID: id, PermissionID: permissionIDs[name]}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&models.User{}).Where("role_id = ?", 0).Update("role_id", consts.RoleMerchantID).Error
+	})
+}
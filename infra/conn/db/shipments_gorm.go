@@ -0,0 +1,45 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"next-oms/app/domain"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+)
+
+func (dc DatabaseClient) GetShipmentByConsignmentID(conID string) (*domain.Shipment, *errors.RestErr) {
+	var resp domain.Shipment
+
+	res := dc.DB.Model(&models.Shipment{}).Where("consignment_id = ?", conID).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("shipment of " + conID))
+		return nil, errors.NewNotFoundError("shipment not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting shipment by consignment id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+// createShipment stores the shipment of an order, it runs on the caller's transaction
+func createShipment(tx *gorm.DB, shipment *domain.Shipment) error {
+	return tx.Create(&models.Shipment{
+		ConsignmentID: shipment.ConsignmentID,
+		StoreID:       shipment.StoreID,
+		RecipientCity: shipment.RecipientCity,
+		RecipientZone: shipment.RecipientZone,
+		RecipientArea: shipment.RecipientArea,
+		DeliveryType:  shipment.DeliveryType,
+		ItemType:      shipment.ItemType,
+		ItemQuantity:  shipment.ItemQuantity,
+		ItemWeight:    shipment.ItemWeight,
+		ItemLength:    shipment.ItemLength,
+		ItemWidth:     shipment.ItemWidth,
+		ItemHeight:    shipment.ItemHeight,
+	}).Error
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"next-oms/app/domain"
+	"next-oms/app/serializers"
+	"next-oms/app/utils/msgutil"
+	"next-oms/infra/conn/db/models"
+	"next-oms/infra/errors"
+	"strconv"
+)
+
+func (dc DatabaseClient) SaveStore(store *domain.Store) (*domain.Store, *errors.RestErr) {
+	mStore := &models.Store{
+		UserID:       store.UserID,
+		CompanyID:    store.CompanyID,
+		Name:         store.Name,
+		ContactPhone: store.ContactPhone,
+		Address:      store.Address,
+		CityID:       store.CityID,
+		ZoneID:       store.ZoneID,
+		AreaID:       store.AreaID,
+	}
+
+	res := dc.DB.Create(mStore)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when create store", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	store.ID = mStore.ID
+	store.CreatedAt = mStore.CreatedAt
+	store.UpdatedAt = mStore.UpdatedAt
+
+	return store, nil
+}
+
+func (dc DatabaseClient) GetStores(scope *domain.TenantScope, filters *serializers.ListFilters) (domain.Stores, *errors.RestErr) {
+	var resp domain.Stores
+
+	var totalRows int64 = 0
+	tableName := "stores"
+	stmt := applyFilters(applyTenantScope(dc.DB.Model(&models.Store{}), tableName, "user_id", scope), tableName, filters, false)
+	countStmt := applyFilters(applyTenantScope(dc.DB.Model(&models.Store{}), tableName, "user_id", scope), tableName, filters, true)
+
+	res := stmt.Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting stores", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total stores count", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+// GetStoreByID returns the store if it belongs to the tenant of the scope, others are not found
+func (dc DatabaseClient) GetStoreByID(scope *domain.TenantScope, id uint) (*domain.Store, *errors.RestErr) {
+	var resp domain.Store
+
+	res := applyTenantScope(dc.DB.Model(&models.Store{}), "stores", "user_id", scope).Where("stores.id = ?", id).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("store " + strconv.Itoa(int(id))))
+		return nil, errors.NewNotFoundError("store not found")
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting store by id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+// GetStoresByIDs returns the stores of the ids which belong to the tenant of the scope
+func (dc DatabaseClient) GetStoresByIDs(scope *domain.TenantScope, ids []uint) (domain.Stores, *errors.RestErr) {
+	var resp domain.Stores
+
+	res := applyTenantScope(dc.DB.Model(&models.Store{}), "stores", "user_id", scope).Where("stores.id IN ?", ids).Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting stores by ids", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return resp, nil
+}
+
+func (dc DatabaseClient) UpdateStore(store *domain.Store) *errors.RestErr {
+	res := dc.DB.Model(&models.Store{}).Where("id = ?", store.ID).Updates(map[string]interface{}{
+		"name":          store.Name,
+		"contact_phone": store.ContactPhone,
+		"address":       store.Address,
+		"city_id":       store.CityID,
+		"zone_id":       store.ZoneID,
+		"area_id":       store.AreaID,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when updating store by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) DeleteStore(id uint) *errors.RestErr {
+	res := dc.DB.Where("id = ?", id).Delete(&models.Store{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting store by id", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("store " + strconv.Itoa(int(id))))
+		return errors.NewNotFoundError("store not found")
+	}
+
+	return nil
+}
+
+// backfillStoreCompanies gives the stores created before they were scoped to a tenant the company
+// of their owner
+func (dc DatabaseClient) backfillStoreCompanies() error {
+	return dc.DB.Exec(`UPDATE stores
+		JOIN users ON users.id = stores.user_id
+		SET stores.company_id = users.company_id
+		WHERE stores.company_id IS NULL AND users.company_id IS NOT NULL`).Error
+}
@@ -3,11 +3,14 @@ package db
 import (
 	"gorm.io/gorm"
 	"next-oms/app/domain"
+	"next-oms/app/serializers"
 	"next-oms/app/utils/methodsutil"
 	"next-oms/app/utils/msgutil"
 	"next-oms/infra/conn/db/models"
 	"next-oms/infra/errors"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func (dc DatabaseClient) SaveUser(user *domain.User) (*domain.User, *errors.RestErr) {
@@ -50,17 +53,49 @@ func (dc DatabaseClient) UpdateUser(user *domain.User) *errors.RestErr {
 	return nil
 }
 
-func (dc DatabaseClient) UpdatePassword(userID uint, companyID uint, updateValues map[string]interface{}) *errors.RestErr {
-	res := dc.DB.Model(&models.User{}).Where("id = ? AND company_id = ?", userID, companyID).Updates(&updateValues)
+// SetUserPassword stores the new password of the user & adds it to the password history, the
+// history is trimmed to the last historySize passwords
+func (dc DatabaseClient) SetUserPassword(userID uint, hashedPass []byte, historySize int) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password":            hashedPass,
+			"password_changed_at": time.Now(),
+			"first_login":         false,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return addPasswordHistory(tx, userID, string(hashedPass), historySize)
+	})
 
-	if res.Error != nil {
-		dc.lc.Error(msgutil.EntityGenericFailedMsg("updating user by user id"), res.Error)
+	if err != nil {
+		dc.lc.Error(msgutil.EntityGenericFailedMsg("setting user password"), err)
 		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
 	return nil
 }
 
+// GetPasswordHistory returns the hashes of the last passwords of the user, newest first
+func (dc DatabaseClient) GetPasswordHistory(userID uint, limit int) ([]string, *errors.RestErr) {
+	var hashes []string
+
+	err := dc.DB.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password", &hashes).
+		Error
+
+	if err != nil {
+		dc.lc.Error("error occurred when getting password history", err)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return hashes, nil
+}
+
 func (dc DatabaseClient) GetUserByEmail(email string) (*domain.User, error) {
 	user := &domain.User{}
 
@@ -94,20 +129,6 @@ func (dc DatabaseClient) SetLastLoginAt(user *domain.User) error {
 	return nil
 }
 
-func (dc DatabaseClient) ResetPassword(userID int, hashedPass []byte) error {
-	err := dc.DB.Model(&models.User{}).
-		Where("id = ?", userID).
-		Update("password", hashedPass).
-		Error
-
-	if err != nil {
-		dc.lc.Error("error occur when reset password", err)
-		return err
-	}
-
-	return nil
-}
-
 func (dc DatabaseClient) GetTokenUser(id uint) (*domain.VerifyTokenResp, *errors.RestErr) {
 	tempUser := &domain.TempVerifyTokenResp{}
 	var vtUser domain.VerifyTokenResp
@@ -127,7 +148,11 @@ func (dc DatabaseClient) GetTokenUser(id uint) (*domain.VerifyTokenResp, *errors
 		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
 	}
 
-	vtUser.Permissions = strings.Split(tempUser.Permissions, ",")
+	// a role without permissions is still a role, its users just have none
+	vtUser.Permissions = []string{}
+	if tempUser.Permissions != "" {
+		vtUser.Permissions = strings.Split(tempUser.Permissions, ",")
+	}
 
 	return &vtUser, nil
 }
@@ -154,7 +179,7 @@ func (dc DatabaseClient) tokenUserFetchQuery() *gorm.DB {
 				WHEN 3 IN (GROUP_CONCAT(DISTINCT users.role_id)) THEN 1 ELSE 0
 			END
 		) AS super_admin,
-		GROUP_CONCAT(DISTINCT permissions.name) AS permissions
+		COALESCE(GROUP_CONCAT(DISTINCT permissions.name), '') AS permissions
 	`
 
 	return dc.DB.Table("users").
@@ -162,8 +187,336 @@ func (dc DatabaseClient) tokenUserFetchQuery() *gorm.DB {
 		Joins("LEFT JOIN companies ON users.company_id = companies.id").
 		Joins("LEFT JOIN businesses ON companies.business_id = businesses.id").
 		Joins("JOIN roles ON users.role_id = roles.id").
-		Joins("JOIN role_permissions ON roles.id = role_permissions.role_id").
-		Joins("JOIN permissions ON role_permissions.permission_id = permissions.id").
+		Joins("LEFT JOIN role_permissions ON roles.id = role_permissions.role_id").
+		Joins("LEFT JOIN permissions ON role_permissions.permission_id = permissions.id").
 		Where("users.deleted_at IS NULL").
 		Group("users.id")
 }
+
+func (dc DatabaseClient) GetUsersByCompany(companyID uint, filters *serializers.ListFilters) (domain.Users, *errors.RestErr) {
+	var resp domain.Users
+
+	var totalRows int64 = 0
+	tableName := "users"
+	stmt := applyFilters(dc.DB.Model(&models.User{}).Omit("password").Where("company_id = ?", companyID), tableName, filters, false)
+	countStmt := applyFilters(dc.DB.Model(&models.User{}).Where("company_id = ?", companyID), tableName, filters, true)
+
+	res := stmt.Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting users of company", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total users count of company", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+// GetUsers lists the users of every company, deleted lists the soft deleted users instead
+func (dc DatabaseClient) GetUsers(filters *serializers.ListFilters, deleted bool) (domain.Users, *errors.RestErr) {
+	var resp domain.Users
+
+	var totalRows int64 = 0
+	tableName := "users"
+	query := dc.DB.Model(&models.User{})
+	if deleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	stmt := applyFilters(query.Session(&gorm.Session{}).Omit("password"), tableName, filters, false)
+	countStmt := applyFilters(query.Session(&gorm.Session{}), tableName, filters, true)
+
+	res := stmt.Find(&resp)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting users", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.Results = resp
+
+	// count all data
+	errCount := countStmt.Count(&totalRows).Error
+	if errCount != nil {
+		dc.lc.Error("error occurred when getting total users count", errCount)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	filters.TotalRows = totalRows
+	filters.CalculateTotalPageAndRows(totalRows)
+	filters.GeneratePagesPath()
+
+	return resp, nil
+}
+
+// GetDeletedUser returns a soft deleted user, the users that aren't deleted aren't found
+func (dc DatabaseClient) GetDeletedUser(userID uint) (*domain.User, *errors.RestErr) {
+	var resp domain.User
+
+	res := dc.DB.Model(&models.User{}).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", userID).First(&resp)
+
+	if res.RowsAffected == 0 {
+		dc.lc.Warn(msgutil.EntityNotFoundMsg("deleted user " + strconv.Itoa(int(userID))))
+		return nil, errors.NewNotFoundError(errors.ErrRecordNotFound)
+	}
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when getting deleted user by user id", res.Error)
+		return nil, errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return &resp, nil
+}
+
+// DeleteUser soft deletes the user, it can be restored with RestoreUser
+func (dc DatabaseClient) DeleteUser(userID uint) *errors.RestErr {
+	res := dc.DB.Where("id = ?", userID).Delete(&models.User{})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when deleting user", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewNotFoundError(errors.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// RestoreUser brings back a soft deleted user
+func (dc DatabaseClient) RestoreUser(userID uint) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", userID).
+		Update("deleted_at", nil)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when restoring user", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewNotFoundError(errors.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SetUserRole(userID, roleID uint) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Update("role_id", roleID)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting role of user", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SetUserProfilePic(userID uint, profilePic *string) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Update("profile_pic", profilePic)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting profile pic of user", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SetUserCompany(userID uint, companyID *uint) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Update("company_id", companyID)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting company of user", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) SetUserActive(userID uint, active bool) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Update("active", active)
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting user active", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// SetUserVerified marks the email of the user as verified, a verified user is left as it is
+func (dc DatabaseClient) SetUserVerified(userID uint) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).
+		Where("id = ? AND verified_at IS NULL", userID).
+		Update("verified_at", time.Now())
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting user verified", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// SetUserEmail changes the email of the user, the new email isn't verified yet
+func (dc DatabaseClient) SetUserEmail(userID uint, email string) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":       email,
+		"verified_at": nil,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting user email", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// verifyExistingUsers marks the users created before email verification existed as verified,
+// it runs once when the verified_at column is added
+func (dc DatabaseClient) verifyExistingUsers() error {
+	return dc.DB.Model(&models.User{}).
+		Where("verified_at IS NULL").
+		Update("verified_at", gorm.Expr("created_at")).
+		Error
+}
+
+// SetUserTotpSecret stores the secret of a pending enrollment, two factor stays off until a
+// code of the secret is confirmed
+func (dc DatabaseClient) SetUserTotpSecret(userID uint, secret string) *errors.RestErr {
+	res := dc.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":   secret,
+		"two_factor_at": nil,
+	})
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when setting user totp secret", res.Error)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// EnableUserTwoFactor turns two factor on for the pending secret along with new backup codes
+func (dc DatabaseClient) EnableUserTwoFactor(userID uint, backupCodeHashes []string) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("two_factor_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return replaceBackupCodes(tx, userID, backupCodeHashes)
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when enabling user two factor", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+func (dc DatabaseClient) DisableUserTwoFactor(userID uint) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":   nil,
+			"two_factor_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return replaceBackupCodes(tx, userID, nil)
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when disabling user two factor", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// SetUserBackupCodes replaces the backup codes of the user, the unused old ones stop working
+func (dc DatabaseClient) SetUserBackupCodes(userID uint, backupCodeHashes []string) *errors.RestErr {
+	err := dc.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceBackupCodes(tx, userID, backupCodeHashes)
+	})
+
+	if err != nil {
+		dc.lc.Error("error occurred when setting user backup codes", err)
+		return errors.NewInternalServerError(errors.ErrSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// UseBackupCode marks an unused backup code of the user as used, false means there was none
+func (dc DatabaseClient) UseBackupCode(userID uint, backupCodeHash string) (bool, error) {
+	res := dc.DB.Model(&models.BackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, backupCodeHash).
+		Limit(1).
+		Update("used_at", time.Now())
+
+	if res.Error != nil {
+		dc.lc.Error("error occurred when using backup code", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func replaceBackupCodes(tx *gorm.DB, userID uint, backupCodeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.BackupCode{}).Error; err != nil {
+		return err
+	}
+
+	if len(backupCodeHashes) == 0 {
+		return nil
+	}
+
+	var backupCodes []*models.BackupCode
+	for _, hash := range backupCodeHashes {
+		backupCodes = append(backupCodes, &models.BackupCode{UserID: userID, CodeHash: hash})
+	}
+
+	return tx.Create(&backupCodes).Error
+}
+
+func addPasswordHistory(tx *gorm.DB, userID uint, hashedPass string, historySize int) error {
+	if historySize <= 0 {
+		return nil
+	}
+
+	if err := tx.Create(&models.PasswordHistory{UserID: userID, Password: hashedPass}).Error; err != nil {
+		return err
+	}
+
+	var keepIDs []uint
+
+	err := tx.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(historySize).
+		Pluck("id", &keepIDs).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("user_id = ? AND id NOT IN ?", userID, keepIDs).Delete(&models.PasswordHistory{}).Error
+}
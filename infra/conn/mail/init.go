@@ -0,0 +1,18 @@
+package mail
+
+import (
+	"next-oms/app/domain"
+	"next-oms/infra/logger"
+)
+
+var client MailClient
+
+func NewMailClient(lc logger.LogClient) domain.IMailer {
+	startMailer(lc)
+
+	return &MailClient{}
+}
+
+func Client() MailClient {
+	return client
+}
@@ -0,0 +1,196 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"next-oms/app/domain"
+	"next-oms/infra/config"
+	"next-oms/infra/errors"
+	"next-oms/infra/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// transport delivers a single rendered message
+type transport interface {
+	deliver(from string, to []string, msg []byte) error
+}
+
+type MailClient struct {
+	lc        logger.LogClient
+	transport transport
+	from      *mail.Address
+	queue     chan *domain.Mail
+	done      chan struct{}
+	wg        *sync.WaitGroup
+}
+
+func startMailer(lc logger.LogClient) {
+	conf := config.Mail()
+
+	var t transport
+	switch conf.Transport {
+	case "smtp":
+		t = newSmtpTransport(conf)
+	case "outbox":
+		t = newOutboxTransport(lc, conf.OutboxDir)
+	case "log":
+		t = newLogTransport(lc)
+	default:
+		panic("unknown mail transport " + conf.Transport)
+	}
+
+	lc.Info("starting mailer with " + conf.Transport + " transport...")
+
+	client = MailClient{
+		lc:        lc,
+		transport: t,
+		from:      &mail.Address{Name: conf.FromName, Address: conf.From},
+		queue:     make(chan *domain.Mail, conf.QueueSize),
+		done:      make(chan struct{}),
+		wg:        &sync.WaitGroup{},
+	}
+
+	for i := 0; i < conf.Workers; i++ {
+		client.wg.Add(1)
+		go client.work()
+	}
+}
+
+// Send queues the mail for delivery, it fails only when the queue is full
+func (mc MailClient) Send(m *domain.Mail) error {
+	if len(m.To) == 0 {
+		return errors.ErrSendingEmail
+	}
+
+	select {
+	case mc.queue <- m:
+		return nil
+	default:
+		mc.lc.Warn("mail queue is full, dropping mail " + m.Subject)
+		return errors.ErrSendingEmail
+	}
+}
+
+// Close stops taking new mails and waits for the queued ones to be delivered,
+// mails still waiting for a retry are given up
+func (mc MailClient) Close() {
+	close(mc.done)
+	close(mc.queue)
+	mc.wg.Wait()
+}
+
+func (mc MailClient) work() {
+	defer mc.wg.Done()
+
+	for m := range mc.queue {
+		mc.deliver(m)
+	}
+}
+
+func (mc MailClient) deliver(m *domain.Mail) {
+	msg, err := mc.build(m)
+	if err != nil {
+		mc.lc.Error("error occurred when building mail "+m.Subject, err)
+		return
+	}
+
+	conf := config.Mail()
+	wait := time.Duration(conf.RetryInterval) * time.Second
+
+	for attempt := 0; ; attempt++ {
+		err = mc.transport.deliver(mc.from.Address, m.To, msg)
+		if err == nil {
+			return
+		}
+
+		if attempt >= conf.MaxRetries || isPermanent(err) {
+			mc.lc.Error("error occurred when sending mail "+m.Subject+" to "+strings.Join(m.To, ", "), err)
+			return
+		}
+
+		mc.lc.Warn(fmt.Sprintf("sending mail %s failed, retrying in %s: %v", m.Subject, wait, err))
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-mc.done:
+			mc.lc.Error("mailer stopped before sending mail "+m.Subject+" to "+strings.Join(m.To, ", "), err)
+			return
+		}
+	}
+}
+
+// isPermanent reports whether the smtp server rejected the mail for good, those aren't retried
+func isPermanent(err error) bool {
+	tpErr, ok := err.(*textproto.Error)
+	return ok && tpErr.Code >= 500
+}
+
+// build renders the mail as a multipart/alternative message with the text & html bodies
+func (mc MailClient) build(m *domain.Mail) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + mc.from.String(),
+		"To: " + strings.Join(m.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + strconv.FormatInt(time.Now().UnixNano(), 36) + "@" + domainOf(mc.from.Address) + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", m.TextBody},
+		{"text/html; charset=utf-8", m.HTMLBody},
+	}
+
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+
+	return "localhost"
+}
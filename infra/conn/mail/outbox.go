@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"next-oms/infra/logger"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+// outboxTransport writes every message into a directory as an .eml file, meant for local development
+type outboxTransport struct {
+	lc  logger.LogClient
+	dir string
+}
+
+func newOutboxTransport(lc logger.LogClient, dir string) *outboxTransport {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		panic(err)
+	}
+
+	return &outboxTransport{lc: lc, dir: dir}
+}
+
+func (t *outboxTransport) deliver(from string, to []string, msg []byte) error {
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "_" + unsafeFileChars.ReplaceAllString(to[0], "_") + ".eml"
+	path := filepath.Join(t.dir, name)
+
+	if err := os.WriteFile(path, msg, 0o600); err != nil {
+		return err
+	}
+
+	t.lc.Info("mail to " + strings.Join(to, ", ") + " written to " + path)
+	return nil
+}
+
+// logTransport only logs the recipients, the message itself is logged at debug level
+type logTransport struct {
+	lc logger.LogClient
+}
+
+func newLogTransport(lc logger.LogClient) *logTransport {
+	return &logTransport{lc: lc}
+}
+
+func (t *logTransport) deliver(from string, to []string, msg []byte) error {
+	t.lc.Info("mail from " + from + " to " + strings.Join(to, ", ") + " logged")
+	t.lc.Debug("mail", string(msg))
+	return nil
+}
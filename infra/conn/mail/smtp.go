@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+	"next-oms/infra/config"
+)
+
+type smtpTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+func newSmtpTransport(conf *config.MailConfig) *smtpTransport {
+	t := &smtpTransport{addr: net.JoinHostPort(conf.Host, conf.Port)}
+
+	if conf.User != "" {
+		t.auth = smtp.PlainAuth("", conf.User, conf.Pass, conf.Host)
+	}
+
+	return t
+}
+
+// deliver sends the message through the smtp server, STARTTLS is used when the server offers it
+func (t *smtpTransport) deliver(from string, to []string, msg []byte) error {
+	return smtp.SendMail(t.addr, t.auth, from, to, msg)
+}
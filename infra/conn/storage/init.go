@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"net/url"
+	"next-oms/app/domain"
+	"next-oms/infra/config"
+	"next-oms/infra/logger"
+	"strings"
+)
+
+var client domain.IStorage
+
+func NewStorageClient(lc logger.LogClient) domain.IStorage {
+	conf := config.Storage()
+
+	switch conf.Driver {
+	case "local":
+		client = newLocalStorage(conf)
+	case "s3":
+		client = newS3Storage(conf)
+	default:
+		panic("unknown storage driver " + conf.Driver)
+	}
+
+	lc.Info("storing files with " + conf.Driver + " storage...")
+
+	return client
+}
+
+func Client() domain.IStorage {
+	return client
+}
+
+// LocalPath is the path the server serves the local storage directory under, it's empty when
+// the files are served by an s3 compatible storage
+func LocalPath() string {
+	conf := config.Storage()
+	if conf.Driver != "local" {
+		return ""
+	}
+
+	publicURL, err := url.Parse(conf.PublicURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimRight(publicURL.Path, "/")
+}
+
+// publicURL builds the URLs of the keys & finds the keys of the URLs under the public URL
+type publicURL string
+
+func (p publicURL) URL(key string) string {
+	return strings.TrimRight(string(p), "/") + "/" + key
+}
+
+func (p publicURL) Key(url string) (string, bool) {
+	prefix := strings.TrimRight(string(p), "/") + "/"
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(url, prefix), true
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"next-oms/infra/config"
+	"next-oms/infra/errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localStorage keeps the files in a directory, the server serves the directory under the public URL
+type localStorage struct {
+	publicURL
+	dir string
+}
+
+func newLocalStorage(conf *config.StorageConfig) *localStorage {
+	return &localStorage{
+		publicURL: publicURL(conf.PublicURL),
+		dir:       conf.LocalDir,
+	}
+}
+
+func (ls *localStorage) Put(_ context.Context, key, _ string, body []byte) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// written under a temporary name first so a file is never served half written
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func (ls *localStorage) Delete(_ context.Context, key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// path keeps the keys inside the storage directory
+func (ls *localStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if strings.Contains(key, "..") || cleaned == "/" {
+		return "", errors.NewError("invalid storage key " + key)
+	}
+
+	return filepath.Join(ls.dir, cleaned), nil
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -15,8 +16,11 @@ var (
 	ErrUpdateLastLogin           = NewError("failed to update last login")
 	ErrNoContextUser             = NewError("failed to get user from context")
 	ErrInvalidRefreshToken       = NewError("invalid refresh_token")
+	ErrSessionNotFound           = NewError("session not found")
+	ErrRefreshTokenReused        = NewError("refresh_token was already used, please log in again")
 	ErrInvalidAccessToken        = NewError("invalid access_token")
 	ErrInvalidPasswordResetToken = NewError("invalid reset_token")
+	ErrInvalidVerifyToken        = NewError("invalid verification token")
 	ErrInvalidRefreshUuid        = NewError("invalid refresh_uuid")
 	ErrInvalidAccessUuid         = NewError("invalid refresh_uuid")
 	ErrInvalidJwtSigningMethod   = NewError("invalid signing method while parsing jwt")
@@ -24,7 +28,21 @@ var (
 	ErrDeleteOldTokenUuid        = NewError("failed to delete old token uuids")
 	ErrSendingEmail              = NewError("failed to send email")
 	ErrNotAdmin                  = NewError("not admin")
+	ErrUserInactive              = NewError("user is deactivated")
+	ErrInvalidAPIKey             = NewError("invalid api key")
+	ErrInvalidTwoFactorChallenge = NewError("invalid or expired challenge_token")
+	ErrInvalidTwoFactorCode      = NewError("invalid two factor code")
+	ErrInvalidEnrollmentCode     = NewError("invalid or expired enrollment_code")
+	ErrPasswordExpired           = NewError("password has expired, reset it through forgot password")
+	ErrLoginLocked               = NewError("too many failed login attempts, try again later")
+	ErrEmailNotVerified          = NewError("email is not verified, check your inbox for the verification link")
+	ErrInvalidStatusTransition   = NewError("invalid order status transition")
+	ErrOrderStatusChanged        = NewError("order status changed concurrently")
+	ErrNoActiveRateCard          = NewError("no active rate card")
+	ErrInvalidIDParam            = NewError("invalid id param")
 	ErrEmptyRedisKeyValue        = NewError("empty redis key or value")
+	ErrIdempotencyKeyInUse       = "a request with the same Idempotency-Key is in progress"
+	ErrIdempotencyKeyReused      = "Idempotency-Key was already used with a different request body"
 	ErrSomethingWentWrong        = "something went wrong"
 	ErrRecordNotFound            = "record not found"
 )
@@ -36,6 +54,8 @@ type RestErr struct {
 	Status int `json:"status"`
 	// example: bad_request
 	Error string `json:"error"`
+	// every rule the request broke, when there's more than one
+	Details []string `json:"details,omitempty"`
 }
 
 func NewError(msg string) error {
@@ -73,3 +93,70 @@ func NewUnauthorizedError(message string) *RestErr {
 		Error:   "unauthorized",
 	}
 }
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusForbidden,
+		Error:   "forbidden",
+	}
+}
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusConflict,
+		Error:   "conflict",
+	}
+}
+
+func NewUnprocessableEntityError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusUnprocessableEntity,
+		Error:   "unprocessable_entity",
+	}
+}
+
+func NewTooManyRequestsError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusTooManyRequests,
+		Error:   "too_many_requests",
+	}
+}
+
+func NewPayloadTooLargeError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusRequestEntityTooLarge,
+		Error:   "payload_too_large",
+	}
+}
+
+func NewUnsupportedMediaTypeError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusUnsupportedMediaType,
+		Error:   "unsupported_media_type",
+	}
+}
+
+// PasswordPolicyError lists the rules of the password policy a password broke
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+// NewPasswordPolicyError returns the violations of the password policy as a bad request
+func NewPasswordPolicyError(violations []string) *RestErr {
+	return &RestErr{
+		Message: "password doesn't meet the password policy",
+		Status:  http.StatusBadRequest,
+		Error:   "bad_request",
+		Details: violations,
+	}
+}
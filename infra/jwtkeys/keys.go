@@ -0,0 +1,209 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"next-oms/infra/config"
+	"next-oms/infra/logger"
+	"os"
+	"sort"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// verificationKey is a public key access tokens are verified with, along with the alg it's used for
+type verificationKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+type keySet struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	keys       map[string]*verificationKey
+}
+
+var set keySet
+
+// Load reads the keys the access tokens are signed & verified with. With HS256 the access token
+// secret is used, with RS256 or EdDSA the private key of SigningKeyFile signs the tokens under the
+// SigningKeyID kid, the public keys of VerificationKeyFiles keep the tokens of the previous keys
+// valid while the keys are rotated
+func Load(lc logger.LogClient) {
+	conf := config.Jwt()
+
+	switch conf.SigningMethod {
+	case "", jwt.SigningMethodHS256.Alg():
+		set = keySet{
+			method:     jwt.SigningMethodHS256,
+			signingKey: []byte(conf.AccessTokenSecret),
+		}
+		lc.Info("access tokens are signed with HS256")
+		return
+	case jwt.SigningMethodRS256.Alg(), AlgorithmEdDSA:
+	default:
+		panic("unsupported jwt signing method " + conf.SigningMethod)
+	}
+
+	if conf.SigningKeyID == "" {
+		panic("jwt signing key id is required for " + conf.SigningMethod)
+	}
+
+	signingKey, err := readPrivateKey(conf.SigningKeyFile)
+	if err != nil {
+		panic(err)
+	}
+
+	set = keySet{
+		signingKey: signingKey,
+		keyID:      conf.SigningKeyID,
+		keys:       map[string]*verificationKey{},
+	}
+
+	switch key := signingKey.(type) {
+	case *rsa.PrivateKey:
+		set.method = jwt.SigningMethodRS256
+		set.keys[conf.SigningKeyID] = &verificationKey{alg: set.method.Alg(), key: &key.PublicKey}
+	case ed25519.PrivateKey:
+		set.method = SigningMethodEdDSA
+		set.keys[conf.SigningKeyID] = &verificationKey{alg: set.method.Alg(), key: key.Public()}
+	default:
+		panic(fmt.Sprintf("unsupported jwt signing key type %T", signingKey))
+	}
+
+	if set.method.Alg() != conf.SigningMethod {
+		panic("jwt signing key " + conf.SigningKeyFile + " is not a " + conf.SigningMethod + " key")
+	}
+
+	for kid, file := range conf.VerificationKeyFiles {
+		if kid == conf.SigningKeyID {
+			continue
+		}
+
+		key, err := readPublicKey(file)
+		if err != nil {
+			panic(err)
+		}
+
+		set.keys[kid] = key
+	}
+
+	lc.Info(fmt.Sprintf("access tokens are signed with %s key %s, %d verification keys loaded", conf.SigningMethod, conf.SigningKeyID, len(set.keys)))
+}
+
+// SigningMethod is the method new access tokens are signed with
+func SigningMethod() jwt.SigningMethod {
+	return set.method
+}
+
+// Sign signs the access token claims, the kid header is set for asymmetric keys
+func Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(set.method, claims)
+
+	if set.keyID != "" {
+		token.Header["kid"] = set.keyID
+	}
+
+	return token.SignedString(set.signingKey)
+}
+
+// Keyfunc finds the key an access token is verified with by its kid, the alg of the token has to
+// be the alg of the key so a public key can't be passed off as an HMAC secret
+func Keyfunc(t *jwt.Token) (interface{}, error) {
+	if set.keys == nil {
+		if t.Method.Alg() != set.method.Alg() {
+			return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
+		}
+		return set.signingKey, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unexpected jwt key id=%v", t.Header["kid"])
+	}
+
+	if t.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("unexpected jwt signing method=%v for key id=%v", t.Header["alg"], kid)
+	}
+
+	return key.key, nil
+}
+
+// KeyIDs returns the kids of the verification keys in order
+func KeyIDs() []string {
+	kids := make([]string, 0, len(set.keys))
+	for kid := range set.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
+func readPrivateKey(file string) (crypto.PrivateKey, error) {
+	block, err := readPem(file)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected pem block %q in %s", block.Type, file)
+	}
+}
+
+func readPublicKey(file string) (*verificationKey, error) {
+	block, err := readPem(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected pem block %q in %s", block.Type, file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return &verificationKey{alg: jwt.SigningMethodRS256.Alg(), key: key}, nil
+	case ed25519.PublicKey:
+		return &verificationKey{alg: AlgorithmEdDSA, key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported jwt verification key type %T in %s", key, file)
+	}
+}
+
+func readPem(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem data found in %s", file)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// registered with the default registry, the metrics server exposes them along with the echo metrics
+var (
+	LoginFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "login_failures_total",
+		Help: "Number of logins rejected because of wrong credentials.",
+	})
+
+	LoginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_lockouts_total",
+		Help: "Number of times an email or ip got locked out after too many failed logins.",
+	}, []string{"scope"})
+
+	LoginLockedAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "login_locked_attempts_total",
+		Help: "Number of logins rejected because the email or ip was locked out.",
+	})
+)